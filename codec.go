@@ -0,0 +1,514 @@
+package opensrs
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Marshaler is implemented by types that encode themselves as an OPS
+// scalar item.
+type Marshaler interface {
+	MarshalOPS() (string, error)
+}
+
+// Unmarshaler is implemented by types that decode themselves from an OPS
+// scalar item.
+type Unmarshaler interface {
+	UnmarshalOPS(value string) error
+}
+
+var (
+	marshalerType       = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// TimeLayout is the layout of timestamps exchanged with OpenSRS.
+const TimeLayout = "2006-01-02 15:04:05"
+
+// UnsupportedTypeError is returned when a value cannot be encoded.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return "opensrs: unsupported type: " + e.Type.String()
+}
+
+// field describes a struct field that takes part in encoding. Fields are
+// named by their `opensrs:"name,omitempty"` tag, or by their lower-cased
+// Go name. A tag of "-" skips the field and the fields of embedded structs
+// without a tag are treated as fields of the outer struct.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.([]field)
+}
+
+func typeFields(t reflect.Type) []field {
+	var fields []field
+	depth := map[string]int{}
+
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tag, hasTag := sf.Tag.Lookup("opensrs")
+			if tag == "-" {
+				continue
+			}
+			idx := append(append([]int{}, index...), i)
+
+			if sf.Anonymous && !hasTag && sf.Type.Kind() == reflect.Struct {
+				walk(sf.Type, idx)
+				continue
+			}
+			if sf.PkgPath != "" {
+				continue
+			}
+
+			name, opts := tag, ""
+			if i := strings.Index(tag, ","); i >= 0 {
+				name, opts = tag[:i], tag[i+1:]
+			}
+			if name == "" {
+				name = strings.ToLower(sf.Name)
+			}
+
+			// A field of an outer struct hides the fields of the same name
+			// in embedded structs.
+			if d, ok := depth[name]; ok {
+				if d <= len(idx) {
+					continue
+				}
+				for j := range fields {
+					if fields[j].name == name {
+						fields = append(fields[:j], fields[j+1:]...)
+						break
+					}
+				}
+			}
+			depth[name] = len(idx)
+
+			fields = append(fields, field{
+				name:      name,
+				index:     idx,
+				omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+			})
+		}
+	}
+	walk(t, nil)
+	return fields
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface().(time.Time).IsZero()
+		}
+		// Unlike encoding/json, omitempty also leaves out struct values
+		// whose fields are all unset.
+		return v.IsZero()
+	}
+	return false
+}
+
+// encodeItem encodes value as an item named key. ok is false when the
+// value is nil and the item has to be left out.
+func encodeItem(key string, value reflect.Value) (item Item, ok bool, err error) {
+	item.Key = key
+	v, err := encodeValue(value)
+	if err != nil {
+		return item, false, fmt.Errorf("%s: %w", key, err)
+	}
+	switch v := v.(type) {
+	case string:
+		item.Value = v
+	case DtAssoc:
+		item.DtAssoc = &v
+	case DtArray:
+		item.DtArray = &v
+	default:
+		return item, false, nil
+	}
+	return item, true, nil
+}
+
+// encodeValue converts v to a string, DtAssoc or DtArray. It returns nil
+// for nil pointers and interfaces.
+func encodeValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	t := v.Type()
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(v.Elem())
+	}
+
+	if t.Implements(marshalerType) {
+		return v.Interface().(Marshaler).MarshalOPS()
+	}
+	if v.CanAddr() && reflect.PtrTo(t).Implements(marshalerType) {
+		return v.Addr().Interface().(Marshaler).MarshalOPS()
+	}
+	if t == timeType {
+		return v.Interface().(time.Time).Format(TimeLayout), nil
+	}
+	if t.Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	if v.CanAddr() && reflect.PtrTo(t).Implements(textMarshalerType) {
+		b, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return "1", nil
+		}
+		return "0", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, t.Bits()), nil
+	case reflect.Struct:
+		dt := DtAssoc{}
+		for _, f := range cachedFields(t) {
+			fv := v.FieldByIndex(f.index)
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			item, ok, err := encodeItem(f.name, fv)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				dt.ItemList = append(dt.ItemList, item)
+			}
+		}
+		return dt, nil
+	case reflect.Map: // DtAssoc
+		// Keys are sorted so the same request always produces the same
+		// envelope, and therefore the same signature.
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		for _, k := range v.MapKeys() {
+			key := fmt.Sprint(k.Interface())
+			keys = append(keys, key)
+			values[key] = v.MapIndex(k)
+		}
+		sort.Strings(keys)
+
+		dt := DtAssoc{}
+		for _, key := range keys {
+			item, ok, err := encodeItem(key, values[key])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				dt.ItemList = append(dt.ItemList, item)
+			}
+		}
+		return dt, nil
+	case reflect.Slice, reflect.Array: // DtArray
+		if t.Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(bytesOf(v)), nil
+		}
+		dt := DtArray{}
+		for i := 0; i < v.Len(); i++ {
+			item, ok, err := encodeItem(strconv.Itoa(i), v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				// Array positions are significant, keep the slot.
+				item = Item{Key: strconv.Itoa(i)}
+			}
+			dt.ItemList = append(dt.ItemList, item)
+		}
+		return dt, nil
+	}
+	return nil, &UnsupportedTypeError{Type: t}
+}
+
+func bytesOf(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+	b := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(b), v)
+	return b
+}
+
+// decodeItem stores the content of item in v, which must be settable.
+// Items whose shape does not fit v (a scalar for a struct, say) are left
+// out, so the API can add structure without breaking older clients.
+func decodeItem(item *Item, v reflect.Value) error {
+	return (&decoder{}).decode(item, v)
+}
+
+// decoder holds the state of a single decode. In strict mode it records
+// the items that decodeItem would silently leave out.
+type decoder struct {
+	strict bool
+	path   []string
+	issues []DecodeIssue
+}
+
+func (d *decoder) decode(item *Item, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(item, v.Elem())
+	}
+
+	scalar := item.isScalar()
+	assoc := item.assoc()
+
+	if v.CanAddr() && reflect.PtrTo(v.Type()).Implements(unmarshalerType) {
+		if !scalar {
+			d.mismatch(item, v)
+			return nil
+		}
+		return v.Addr().Interface().(Unmarshaler).UnmarshalOPS(item.text())
+	}
+	if v.Type() == timeType {
+		if !scalar {
+			d.mismatch(item, v)
+			return nil
+		}
+		if item.text() == "" {
+			return nil
+		}
+		t, err := time.Parse(TimeLayout, item.text())
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if v.CanAddr() && reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		if !scalar {
+			d.mismatch(item, v)
+			return nil
+		}
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(item.text()))
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(item.decode()))
+		}
+	case reflect.String:
+		if !scalar {
+			d.mismatch(item, v)
+			return nil
+		}
+		v.SetString(item.text())
+	case reflect.Bool:
+		if !scalar {
+			d.mismatch(item, v)
+		} else {
+			b, err := parseBool(item.text())
+			if err != nil {
+				return err
+			}
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !scalar {
+			d.mismatch(item, v)
+		} else if item.text() != "" {
+			n, err := strconv.ParseInt(strings.TrimSpace(item.text()), 10, v.Type().Bits())
+			if err != nil {
+				return err
+			}
+			v.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !scalar {
+			d.mismatch(item, v)
+		} else if item.text() != "" {
+			n, err := strconv.ParseUint(strings.TrimSpace(item.text()), 10, v.Type().Bits())
+			if err != nil {
+				return err
+			}
+			v.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		if !scalar {
+			d.mismatch(item, v)
+		} else if item.text() != "" {
+			n, err := strconv.ParseFloat(strings.TrimSpace(item.text()), v.Type().Bits())
+			if err != nil {
+				return err
+			}
+			v.SetFloat(n)
+		}
+	case reflect.Struct:
+		if assoc == nil {
+			d.mismatch(item, v)
+			return nil
+		}
+		fields := cachedFields(v.Type())
+		for i := range assoc.ItemList {
+			child := &assoc.ItemList[i]
+			f, ok := lookupField(fields, child.Key)
+			if !ok {
+				d.unknown(child, v)
+				continue
+			}
+			if err := d.child(child.Key, child, fieldByIndex(v, f.index)); err != nil {
+				return fmt.Errorf("%s: %w", child.Key, err)
+			}
+		}
+	case reflect.Map:
+		if assoc == nil || v.Type().Key().Kind() != reflect.String {
+			d.mismatch(item, v)
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		for i := range assoc.ItemList {
+			child := &assoc.ItemList[i]
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := d.child(child.Key, child, elem); err != nil {
+				return fmt.Errorf("%s: %w", child.Key, err)
+			}
+			v.SetMapIndex(reflect.ValueOf(child.Key).Convert(v.Type().Key()), elem)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if !scalar {
+				d.mismatch(item, v)
+				return nil
+			}
+			b, err := base64.StdEncoding.DecodeString(item.text())
+			if err != nil {
+				return err
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		if item.DtArray == nil {
+			d.mismatch(item, v)
+			return nil
+		}
+		s := reflect.MakeSlice(v.Type(), len(item.DtArray.ItemList), len(item.DtArray.ItemList))
+		for i := range item.DtArray.ItemList {
+			if err := d.child(strconv.Itoa(i), &item.DtArray.ItemList[i], s.Index(i)); err != nil {
+				return fmt.Errorf("%d: %w", i, err)
+			}
+		}
+		v.Set(s)
+	default:
+		return fmt.Errorf("opensrs: cannot decode into %s", v.Type())
+	}
+	return nil
+}
+
+// child decodes a nested item, keeping track of its path for issues.
+func (d *decoder) child(key string, item *Item, v reflect.Value) error {
+	d.path = append(d.path, key)
+	err := d.decode(item, v)
+	d.path = d.path[:len(d.path)-1]
+	return err
+}
+
+// unknown records an item for which struct v has no field.
+func (d *decoder) unknown(item *Item, v reflect.Value) {
+	if !d.strict {
+		return
+	}
+	d.issues = append(d.issues, DecodeIssue{
+		Path:   strings.Join(append(d.path[:len(d.path):len(d.path)], item.Key), "/"),
+		Reason: fmt.Sprintf("unknown key for %s", v.Type()),
+	})
+}
+
+// mismatch records an item whose shape does not fit v. Empty items fit
+// anything.
+func (d *decoder) mismatch(item *Item, v reflect.Value) {
+	if !d.strict || item.isScalar() && strings.TrimSpace(item.text()) == "" {
+		return
+	}
+	d.issues = append(d.issues, DecodeIssue{
+		Path:   strings.Join(d.path, "/"),
+		Reason: fmt.Sprintf("unexpected %s for %s", item.kind(), v.Type()),
+	})
+}
+
+func lookupField(fields []field, key string) (field, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but allocates nil
+// embedded pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,253 @@
+package opensrs
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type upperString string
+
+func (u upperString) MarshalOPS() (string, error) {
+	return strings.ToUpper(string(u)), nil
+}
+
+func (u *upperString) UnmarshalOPS(value string) error {
+	*u = upperString(strings.ToLower(value))
+	return nil
+}
+
+type codecInner struct {
+	Name  string   `opensrs:"name"`
+	Flags []string `opensrs:"flags,omitempty"`
+}
+
+type codecTest struct {
+	BaseRequest
+	Custom   upperString       `opensrs:"custom"`
+	Enabled  Bool              `opensrs:"enabled"`
+	Plain    bool              `opensrs:"plain,omitempty"`
+	Inner    codecInner        `opensrs:"inner"`
+	List     []codecInner      `opensrs:"list,omitempty"`
+	Extra    map[string]string `opensrs:"extra,omitempty"`
+	Skipped  string            `opensrs:"-"`
+	Untagged string
+}
+
+func TestCodecStructEncoding(t *testing.T) {
+	v := codecTest{
+		BaseRequest: BaseRequest{Action: "LOOKUP", Object: "DOMAIN", Protocol: "XCP"},
+		Custom:      "mixed",
+		Enabled:     true,
+		Inner:       codecInner{Name: "inner"},
+		Skipped:     "never sent",
+		Untagged:    "x",
+	}
+
+	b, err := ToXml(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `<OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc>` +
+		`<item key="action">LOOKUP</item><item key="object">DOMAIN</item><item key="protocol">XCP</item>` +
+		`<item key="custom">MIXED</item><item key="enabled">1</item>` +
+		`<item key="inner"><dt_assoc><item key="name">inner</item></dt_assoc></item>` +
+		`<item key="untagged">x</item>` +
+		`</dt_assoc></data_block></body></OPS_envelope>`
+	if string(b) != want {
+		t.Errorf("encoding, got\n%s\nwant\n%s", b, want)
+	}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	in := codecTest{
+		BaseRequest: BaseRequest{Action: "LOOKUP", Object: "DOMAIN", Protocol: "XCP"},
+		Custom:      "mixed",
+		Enabled:     true,
+		Plain:       true,
+		Inner:       codecInner{Name: "inner", Flags: []string{"a", "b"}},
+		List:        []codecInner{{Name: "one"}, {Name: "two"}},
+		Extra:       map[string]string{"k": "v"},
+		Untagged:    "x",
+	}
+
+	b, err := ToXml(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out codecTest
+	if err := FromXml(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip, got\n%+v\nwant\n%+v", out, in)
+	}
+}
+
+func TestCodecDecodeIntoMap(t *testing.T) {
+	b := []byte(`<OPS_envelope><body><data_block><dt_assoc>` +
+		`<item key="a">1</item>` +
+		`<item key="b"><dt_array><item key="0">x</item><item key="1"><dt_assoc><item key="c">y</item></dt_assoc></item></dt_array></item>` +
+		`</dt_assoc></data_block></body></OPS_envelope>`)
+
+	var m Map
+	if err := FromXml(b, &m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Map{
+		"a": "1",
+		"b": []interface{}{"x", Map{"c": "y"}},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %#v, want %#v", m, want)
+	}
+}
+
+func TestCodecDecodeSkipsMismatchedShapes(t *testing.T) {
+	b := []byte(`<OPS_envelope><body><data_block><dt_assoc>` +
+		`<item key="action"><dt_assoc><item key="x">y</item></dt_assoc></item>` +
+		`<item key="inner">scalar</item>` +
+		`<item key="object">DOMAIN</item>` +
+		`</dt_assoc></data_block></body></OPS_envelope>`)
+
+	var out codecTest
+	if err := FromXml(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Action != "" || out.Inner.Name != "" || out.Object != "DOMAIN" {
+		t.Errorf("unexpected result %+v", out)
+	}
+}
+
+func TestCodecDecodeInvalidBool(t *testing.T) {
+	b := []byte(`<OPS_envelope><body><data_block><dt_assoc>` +
+		`<item key="enabled">maybe</item>` +
+		`</dt_assoc></data_block></body></OPS_envelope>`)
+
+	var out codecTest
+	err := FromXml(b, &out)
+	if err == nil || !strings.Contains(err.Error(), "enabled") {
+		t.Errorf("want error naming the field, got %v", err)
+	}
+}
+
+func TestFromXmlNeedsPointer(t *testing.T) {
+	var out codecTest
+	if err := FromXml([]byte(`<OPS_envelope/>`), out); err == nil {
+		t.Errorf("want error for non-pointer target")
+	}
+}
+
+type scalarTest struct {
+	Int     int        `opensrs:"int"`
+	Int64   int64      `opensrs:"int64"`
+	Uint    uint16     `opensrs:"uint"`
+	Float   float64    `opensrs:"float"`
+	Ptr     *string    `opensrs:"ptr"`
+	NilPtr  *string    `opensrs:"nil_ptr"`
+	BoolPtr *Bool      `opensrs:"bool_ptr"`
+	Time    time.Time  `opensrs:"time"`
+	TimePtr *time.Time `opensrs:"time_ptr"`
+	IP      net.IP     `opensrs:"ip"`
+	Bytes   []byte     `opensrs:"bytes"`
+	Zero    int        `opensrs:"zero,omitempty"`
+}
+
+func TestCodecScalarTypes(t *testing.T) {
+	ts := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	yes := Bool(true)
+	in := scalarTest{
+		Int:     -42,
+		Int64:   1 << 40,
+		Uint:    7,
+		Float:   12.5,
+		Ptr:     String("pointed"),
+		BoolPtr: &yes,
+		Time:    ts,
+		TimePtr: &ts,
+		IP:      net.ParseIP("192.0.2.1"),
+		Bytes:   []byte("raw"),
+	}
+
+	b, err := ToXml(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `<OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc>` +
+		`<item key="int">-42</item><item key="int64">1099511627776</item><item key="uint">7</item>` +
+		`<item key="float">12.5</item><item key="ptr">pointed</item><item key="bool_ptr">1</item>` +
+		`<item key="time">2026-03-04 05:06:07</item><item key="time_ptr">2026-03-04 05:06:07</item>` +
+		`<item key="ip">192.0.2.1</item><item key="bytes">cmF3</item>` +
+		`</dt_assoc></data_block></body></OPS_envelope>`
+	if string(b) != want {
+		t.Errorf("encoding, got\n%s\nwant\n%s", b, want)
+	}
+
+	var out scalarTest
+	if err := FromXml(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip, got\n%+v\nwant\n%+v", out, in)
+	}
+}
+
+func TestCodecUnsupportedType(t *testing.T) {
+	_, err := ToXml(Map{"attributes": Map{"callback": func() {}}})
+
+	var unsupported *UnsupportedTypeError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("want UnsupportedTypeError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "callback") {
+		t.Errorf("want error to name the item, got %v", err)
+	}
+}
+
+func TestNewRequestRejectsUnsupportedType(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.NewRequest("POST", "", executeRequest{
+		BaseRequest: BaseRequest{Action: "LOOKUP", Object: "DOMAIN", Protocol: "XCP"},
+		Attributes:  Map{"c": make(chan int)},
+	})
+	if err == nil {
+		t.Fatal("want error for unsupported attribute type")
+	}
+}
+
+func TestCodecOmitsEmptyStructs(t *testing.T) {
+	type block struct {
+		Max  string   `opensrs:"maximum,omitempty"`
+		TLDs []string `opensrs:"tlds,omitempty"`
+	}
+	type request struct {
+		Value    block  `opensrs:"value,omitempty"`
+		Pointer  *block `opensrs:"pointer,omitempty"`
+		Explicit *block `opensrs:"explicit,omitempty"`
+		Kept     block  `opensrs:"kept"`
+		Flag     *Bool  `opensrs:"flag,omitempty"`
+	}
+
+	b, err := ToXml(request{Explicit: &block{}, Flag: NewBool(false)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `<OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc>` +
+		`<item key="explicit"><dt_assoc></dt_assoc></item>` +
+		`<item key="kept"><dt_assoc></dt_assoc></item>` +
+		`<item key="flag">0</item>` +
+		`</dt_assoc></data_block></body></OPS_envelope>`
+	if string(b) != want {
+		t.Errorf("got\n%s\nwant\n%s", b, want)
+	}
+}
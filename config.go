@@ -0,0 +1,127 @@
+package opensrs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvUsername    = "OPENSRS_USERNAME"
+	EnvAPIKey      = "OPENSRS_API_KEY"
+	EnvEnvironment = "OPENSRS_ENVIRONMENT"
+	EnvBaseURL     = "OPENSRS_BASE_URL"
+	EnvTimeout     = "OPENSRS_TIMEOUT"
+	EnvUserAgent   = "OPENSRS_USER_AGENT"
+	EnvConfigFile  = "OPENSRS_CONFIG"
+)
+
+// ErrMissingCredentials is returned when a Config has no username or key.
+var ErrMissingCredentials = errors.New("opensrs: missing reseller username or api key")
+
+// Config holds the settings needed to build a Client. It can be loaded from
+// a JSON file with LoadConfig or from the environment with ConfigFromEnv.
+type Config struct {
+	Username    string `json:"username"`
+	APIKey      string `json:"api_key"`
+	Environment string `json:"environment,omitempty"`
+	BaseURL     string `json:"base_url,omitempty"`
+	// Timeout is a duration such as "30s".
+	Timeout   string `json:"timeout,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// LoadConfig reads a JSON config file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("opensrs: parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ConfigFromEnv builds a Config from the OPENSRS_* environment variables.
+// When OPENSRS_CONFIG names a file, it is loaded first and the other
+// variables override its values.
+func ConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		var err error
+		cfg, err = LoadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for env, field := range map[string]*string{
+		EnvUsername:    &cfg.Username,
+		EnvAPIKey:      &cfg.APIKey,
+		EnvEnvironment: &cfg.Environment,
+		EnvBaseURL:     &cfg.BaseURL,
+		EnvTimeout:     &cfg.Timeout,
+		EnvUserAgent:   &cfg.UserAgent,
+	} {
+		if v, ok := os.LookupEnv(env); ok {
+			*field = v
+		}
+	}
+	return cfg, nil
+}
+
+// Options converts the config into client options. BaseURL takes
+// precedence over Environment.
+func (cfg *Config) Options() ([]Option, error) {
+	var opts []Option
+	if cfg.Environment != "" {
+		env, err := ParseEnvironment(cfg.Environment)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithEnvironment(env))
+	}
+	if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("opensrs: invalid timeout %q: %w", cfg.Timeout, err)
+		}
+		opts = append(opts, WithTimeout(d))
+	}
+	if cfg.UserAgent != "" {
+		opts = append(opts, WithUserAgent(cfg.UserAgent))
+	}
+	return opts, nil
+}
+
+// NewClientFromConfig builds a Client from cfg. opts are applied after the
+// settings from cfg.
+func NewClientFromConfig(cfg *Config, opts ...Option) (*Client, error) {
+	if cfg.Username == "" || cfg.APIKey == "" {
+		return nil, ErrMissingCredentials
+	}
+	cfgOpts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(cfg.Username, cfg.APIKey, append(cfgOpts, opts...)...), nil
+}
+
+// NewClientFromEnv builds a Client from the OPENSRS_* environment variables,
+// see ConfigFromEnv.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewClientFromConfig(cfg, opts...)
+}
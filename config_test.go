@@ -0,0 +1,112 @@
+package opensrs
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "opensrs-config-*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func setEnv(t *testing.T, env map[string]string) func() {
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfigFile(t, `{"username":"reseller","api_key":"key","environment":"test","timeout":"10s"}`)
+	defer os.Remove(path)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c, err := NewClientFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.ResellerUsername != "reseller" || c.ApiKey != "key" {
+		t.Errorf("unexpected credentials %s/%s", c.ResellerUsername, c.ApiKey)
+	}
+	if c.BaseURL != testBaseURL {
+		t.Errorf("want test url, got %s", c.BaseURL)
+	}
+	if c.HttpClient.Timeout != 10*time.Second {
+		t.Errorf("want 10s timeout, got %v", c.HttpClient.Timeout)
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	path := writeConfigFile(t, `{"username":"from-file","api_key":"file-key","environment":"test"}`)
+	defer os.Remove(path)
+
+	defer setEnv(t, map[string]string{
+		EnvConfigFile: path,
+		EnvUsername:   "from-env",
+		EnvBaseURL:    "https://example.test:55443",
+	})()
+
+	c, err := NewClientFromEnv(WithUserAgent("svc/2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.ResellerUsername != "from-env" {
+		t.Errorf("env must override file, got %s", c.ResellerUsername)
+	}
+	if c.ApiKey != "file-key" {
+		t.Errorf("want key from file, got %s", c.ApiKey)
+	}
+	if c.BaseURL != "https://example.test:55443" {
+		t.Errorf("base url must override environment, got %s", c.BaseURL)
+	}
+	if c.UserAgent != "svc/2" {
+		t.Errorf("explicit option not applied, got %s", c.UserAgent)
+	}
+}
+
+func TestNewClientFromConfigMissingCredentials(t *testing.T) {
+	_, err := NewClientFromConfig(&Config{Username: "reseller"})
+	if err != ErrMissingCredentials {
+		t.Errorf("want ErrMissingCredentials, got %v", err)
+	}
+}
+
+func TestConfigInvalidValues(t *testing.T) {
+	if _, err := NewClientFromConfig(&Config{Username: "u", APIKey: "k", Environment: "staging"}); err == nil {
+		t.Errorf("want error for unknown environment")
+	}
+	if _, err := NewClientFromConfig(&Config{Username: "u", APIKey: "k", Timeout: "soon"}); err == nil {
+		t.Errorf("want error for invalid timeout")
+	}
+}
+
+func TestNewClientFromConfigTimeoutWithHTTPClient(t *testing.T) {
+	c, err := NewClientFromConfig(&Config{Username: apiUser, APIKey: apiKey, Timeout: "5s"}, WithHTTPClient(&http.Client{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.HttpClient.Timeout != 5*time.Second {
+		t.Errorf("want 5s timeout from the config, got %v", c.HttpClient.Timeout)
+	}
+}
@@ -0,0 +1,38 @@
+package opensrs
+
+// Contact is a domain contact as used in contact sets.
+type Contact struct {
+	FirstName  string `opensrs:"first_name,omitempty"`
+	LastName   string `opensrs:"last_name,omitempty"`
+	OrgName    string `opensrs:"org_name,omitempty"`
+	Address1   string `opensrs:"address1,omitempty"`
+	Address2   string `opensrs:"address2,omitempty"`
+	Address3   string `opensrs:"address3,omitempty"`
+	City       string `opensrs:"city,omitempty"`
+	State      string `opensrs:"state,omitempty"`
+	PostalCode string `opensrs:"postal_code,omitempty"`
+	// Country is the ISO 3166 alpha-2 code, e.g. "CA".
+	Country string `opensrs:"country,omitempty"`
+	// Phone and Fax use the +1.4165550123 format.
+	Phone string `opensrs:"phone,omitempty"`
+	Fax   string `opensrs:"fax,omitempty"`
+	Email string `opensrs:"email,omitempty"`
+	Lang  string `opensrs:"lang,omitempty"`
+}
+
+// ContactSet holds the contacts of a domain by type. Unset contacts are
+// left out of requests.
+type ContactSet struct {
+	Owner   *Contact `opensrs:"owner,omitempty"`
+	Admin   *Contact `opensrs:"admin,omitempty"`
+	Billing *Contact `opensrs:"billing,omitempty"`
+	Tech    *Contact `opensrs:"tech,omitempty"`
+}
+
+// Nameserver is an entry of a domain's nameserver list.
+type Nameserver struct {
+	Name      string `opensrs:"name"`
+	SortOrder int    `opensrs:"sortorder,omitempty"`
+	IPAddress string `opensrs:"ipaddress,omitempty"`
+	IPv6      string `opensrs:"ipv6,omitempty"`
+}
@@ -0,0 +1,109 @@
+package opensrs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// maxPooledBuffer keeps unusually large responses from pinning memory in
+// the buffer pool.
+const maxPooledBuffer = 4 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// readResponseBody reads r into a copy sized to fit, using a pooled buffer so the
+// read does not grow a fresh slice for every response.
+func readResponseBody(r io.Reader) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf.Bytes()...), nil
+}
+
+// response is a parsed OPS reply. The envelope is parsed once and both the
+// BaseResponse and the caller's type are decoded from the parsed tree.
+type response struct {
+	body     []byte
+	envelope OPSEnvelope
+	base     BaseResponse
+	raw      *RawResponse
+}
+
+func parseResponse(b []byte) (*response, error) {
+	r := &response{body: b}
+	if err := xml.Unmarshal(b, &r.envelope); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// parseReply is parseResponse for replies to a call, which also decodes
+// the BaseResponse.
+func parseReply(b []byte) (*response, error) {
+	r, err := parseResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.decode(&r.base); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// decode stores the data block in v, which must be a non-nil pointer.
+func (r *response) decode(v interface{}) error {
+	return r.decodeWith(&decoder{}, v)
+}
+
+// decodeStrict is decode, but returns a *StrictDecodeError when the data
+// block holds items that v has no place for.
+func (r *response) decodeStrict(v interface{}) error {
+	d := &decoder{strict: true}
+	if err := r.decodeWith(d, v); err != nil {
+		return err
+	}
+	if len(d.issues) > 0 {
+		return &StrictDecodeError{Issues: d.issues}
+	}
+	return nil
+}
+
+func (r *response) decodeWith(d *decoder, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("opensrs: cannot decode into non-pointer %T", v)
+	}
+	root := r.envelope.Body.DataBlock.item()
+	if s, ok := v.(interface{ setRaw(*RawResponse) }); ok {
+		if r.raw == nil {
+			r.raw = &RawResponse{XML: r.body, root: root}
+		}
+		s.setRaw(r.raw)
+	}
+	if root == nil {
+		return nil
+	}
+	return d.decode(root, rv.Elem())
+}
+
+// parsedFor returns the parsed response if it still belongs to body. An
+// interceptor may have replaced the body after it was parsed.
+func (r *response) parsedFor(body []byte) bool {
+	return r != nil && len(r.body) == len(body) && len(body) > 0 && &r.body[0] == &body[0]
+}
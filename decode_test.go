@@ -0,0 +1,113 @@
+package opensrs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+var benchFixtures = []struct {
+	name string
+	path string
+	new  func() interface{}
+}{
+	{"lookup", "testresponses/domain.lookup.example2.xml", func() interface{} { return &LookupResponse{} }},
+	{"namesuggest", "testresponses/domain.namesuggest.example1.xml", func() interface{} { return &NameSuggestResponse{} }},
+	{"namesuggest_premium", "testresponses/domain.namesuggest.example10.xml", func() interface{} { return &NameSuggestResponse{} }},
+}
+
+func TestReplacedBodyIsDecoded(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.lookup.example1a.xml")
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, respXML)
+	})
+
+	replacement := []byte(readFile(t, "testresponses/domain.lookup.example1b.xml"))
+	client.Interceptors = []Interceptor{
+		func(ctx context.Context, call *Call, next Invoker) error {
+			if err := next(ctx, call); err != nil {
+				return err
+			}
+			call.ResponseBody = replacement
+			call.Response = nil
+			return nil
+		},
+	}
+
+	resp, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attributes.Status != "taken" {
+		t.Errorf("want the replaced body to be decoded, got status %q", resp.Attributes.Status)
+	}
+}
+
+func TestReadResponseBody(t *testing.T) {
+	in := bytes.Repeat([]byte("x"), 100000)
+	first, err := readResponseBody(bytes.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := readResponseBody(bytes.NewReader([]byte("short")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, in) || string(second) != "short" {
+		t.Errorf("pooled buffer leaked data between reads")
+	}
+}
+
+// BenchmarkDecodeTwoPass decodes a reply the way Client.Do used to: once
+// into the typed response and once more into BaseResponse.
+func BenchmarkDecodeTwoPass(b *testing.B) {
+	for _, f := range benchFixtures {
+		body, err := ioutil.ReadFile(f.path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(f.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				data, _ := ioutil.ReadAll(bytes.NewReader(body))
+				if err := FromXml(data, f.new()); err != nil {
+					panic(err)
+				}
+				if err := FromXml(data, &BaseResponse{}); err != nil {
+					panic(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDecodeSinglePass decodes a reply the way Client.Do does now.
+func BenchmarkDecodeSinglePass(b *testing.B) {
+	for _, f := range benchFixtures {
+		body, err := ioutil.ReadFile(f.path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(f.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				data, _ := readResponseBody(bytes.NewReader(body))
+				r, err := parseReply(data)
+				if err != nil {
+					panic(err)
+				}
+				if err := r.decode(f.new()); err != nil {
+					panic(err)
+				}
+			}
+		})
+	}
+}
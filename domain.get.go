@@ -0,0 +1,125 @@
+package opensrs
+
+import (
+	"context"
+	"time"
+)
+
+// GetType selects what GET DOMAIN returns.
+type GetType string
+
+const (
+	GetAllInfo           GetType = "all_info"
+	GetAdmin             GetType = "admin"
+	GetBilling           GetType = "billing"
+	GetOwner             GetType = "owner"
+	GetTech              GetType = "tech"
+	GetNameservers       GetType = "nameservers"
+	GetStatus            GetType = "status"
+	GetExpireAction      GetType = "expire_action"
+	GetWhoisPrivacyState GetType = "whois_privacy_state"
+	GetDomainAuthInfo    GetType = "domain_auth_info"
+	GetForwardingEmail   GetType = "forwarding_email"
+	GetList              GetType = "list"
+)
+
+// GetRequest names the domain directly instead of through a cookie, which
+// the reseller credentials allow for any domain in the portfolio.
+type GetRequest struct {
+	BaseRequest
+	Domain     string               `opensrs:"domain"`
+	Attributes GetRequestAttributes `opensrs:"attributes"`
+}
+
+type GetRequestAttributes struct {
+	Type GetType `opensrs:"type"`
+}
+
+type GetResponse struct {
+	BaseResponse
+	Attributes DomainInfo `opensrs:"attributes"`
+}
+
+// DomainInfo is the result of GET DOMAIN. Each GetType fills in the fields
+// listed under it, GetAllInfo most of them. Fields the reply does not
+// contain are nil, so an unset value is never mistaken for false or zero.
+type DomainInfo struct {
+	// GetAllInfo, GetExpireAction
+	AutoRenew *Bool `opensrs:"auto_renew"`
+	LetExpire *Bool `opensrs:"let_expire"`
+
+	// GetAllInfo
+	AffiliateID        *string    `opensrs:"affiliate_id"`
+	ExpireDate         *time.Time `opensrs:"expiredate"`
+	RegistryCreateDate *time.Time `opensrs:"registry_createdate"`
+	RegistryExpireDate *time.Time `opensrs:"registry_expiredate"`
+	RegistryUpdateDate *time.Time `opensrs:"registry_updatedate"`
+	SponsoringRSP      *Bool      `opensrs:"sponsoring_rsp"`
+	GDPRConsentStatus  *string    `opensrs:"gdpr_consent_status"`
+	TLDData            Map        `opensrs:"tld_data"`
+
+	// GetAllInfo, GetAdmin, GetBilling, GetOwner, GetTech
+	ContactSet ContactSet `opensrs:"contact_set"`
+
+	// GetAllInfo, GetNameservers
+	NameserverList []Nameserver `opensrs:"nameserver_list"`
+
+	// GetStatus
+	LockState              *Bool `opensrs:"lock_state"`
+	CanModify              *Bool `opensrs:"can_modify"`
+	TransferAwayInProgress *Bool `opensrs:"transfer_away_in_progress"`
+	DomainSupports         Map   `opensrs:"domain_supports"`
+
+	// GetWhoisPrivacyState
+	State      *string `opensrs:"state"`
+	Changeable *Bool   `opensrs:"changeable"`
+
+	// GetDomainAuthInfo
+	DomainAuthInfo *string `opensrs:"domain_auth_info"`
+
+	// GetForwardingEmail
+	ForwardingEmail *string `opensrs:"forwarding_email"`
+
+	// GetList
+	Page       *int             `opensrs:"page"`
+	Total      *int             `opensrs:"total"`
+	Remainder  *Bool            `opensrs:"remainder"`
+	DomainList []DomainListItem `opensrs:"domain_list"`
+}
+
+// DomainListItem is a domain of the same profile, as listed by GetList.
+type DomainListItem struct {
+	Domain     string    `opensrs:"domain"`
+	ExpireDate time.Time `opensrs:"expiredate"`
+}
+
+// Get queries the state of a domain in the reseller's portfolio.
+func (s *DomainsService) Get(domain string, t GetType) (*GetResponse, error) {
+	return s.GetContext(context.Background(), domain, t)
+}
+
+// GetContext is like Get but aborts the call when ctx is done.
+func (s *DomainsService) GetContext(ctx context.Context, domain string, t GetType) (*GetResponse, error) {
+	opsResponse := GetResponse{}
+
+	payload := GetRequest{
+		BaseRequest: BaseRequest{
+			Action:   "GET",
+			Object:   "DOMAIN",
+			Protocol: "XCP",
+		},
+		Domain:     domain,
+		Attributes: GetRequestAttributes{Type: t},
+	}
+	req, err := s.Client.NewRequestWithContext(ctx, "POST", "", payload)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Client.Do(req, &opsResponse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &opsResponse, nil
+}
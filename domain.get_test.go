@@ -0,0 +1,141 @@
+package opensrs
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// https://domains.opensrs.guide/docs/get-domain
+func TestGetAllInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.get.all_info.xml")
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body := readBody(t, r)
+
+		// Test request body
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="action">GET</item><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="domain">example.com</item><item key="attributes"><dt_assoc><item key="type">all_info</item></dt_assoc></item></dt_assoc></data_block></body></OPS_envelope>`
+		if string(body) != want {
+			t.Errorf("get request, got\n%s,\nwant\n%s", body, want)
+		}
+
+		// Test req method
+		testMethod(t, r)
+
+		// Test authentication method
+		testAuth(t, r.Header, string(body))
+
+		fmt.Fprint(w, respXML)
+	})
+
+	resp, err := client.Domains.Get("example.com", GetAllInfo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a := resp.Attributes
+	if want := time.Date(2027, 6, 29, 14, 41, 26, 0, time.UTC); a.ExpireDate == nil || !a.ExpireDate.Equal(want) {
+		t.Errorf("unexpected ExpireDate, want %v, got %v", want, a.ExpireDate)
+	}
+	if want := time.Date(2021, 6, 29, 14, 41, 25, 0, time.UTC); a.RegistryCreateDate == nil || !a.RegistryCreateDate.Equal(want) {
+		t.Errorf("unexpected RegistryCreateDate, want %v, got %v", want, a.RegistryCreateDate)
+	}
+	if !reflect.DeepEqual(a.AutoRenew, NewBool(true)) || !reflect.DeepEqual(a.LetExpire, NewBool(false)) || !reflect.DeepEqual(a.SponsoringRSP, NewBool(true)) {
+		t.Errorf("unexpected flags %+v", a)
+	}
+	if a.LockState != nil || a.Page != nil {
+		t.Errorf("fields of other types should be nil, got %+v", a)
+	}
+
+	wantOwner := Contact{
+		FirstName:  "John",
+		LastName:   "Smith",
+		OrgName:    "Example Inc.",
+		Address1:   "32 Oak Street",
+		City:       "Santa Clara",
+		State:      "CA",
+		PostalCode: "90210",
+		Country:    "US",
+		Phone:      "+1.4165550123",
+		Email:      "jsmith@example.com",
+	}
+	if a.ContactSet.Owner == nil || !reflect.DeepEqual(*a.ContactSet.Owner, wantOwner) {
+		t.Errorf("unexpected owner, want %+v, got %+v", wantOwner, a.ContactSet.Owner)
+	}
+	if a.ContactSet.Tech == nil || a.ContactSet.Tech.Email != "tech@example.com" {
+		t.Errorf("unexpected tech contact %+v", a.ContactSet.Tech)
+	}
+
+	wantNS := []Nameserver{
+		{Name: "ns1.systemdns.com", IPAddress: "216.40.47.201", SortOrder: 1},
+		{Name: "ns2.systemdns.com", IPAddress: "216.40.47.202", SortOrder: 2},
+	}
+	if !reflect.DeepEqual(a.NameserverList, wantNS) {
+		t.Errorf("unexpected NameserverList, want %+v, got %+v", wantNS, a.NameserverList)
+	}
+}
+
+func TestGetTypes(t *testing.T) {
+	tests := []struct {
+		t     GetType
+		file  string
+		check func(t *testing.T, a DomainInfo)
+	}{
+		{GetStatus, "domain.get.status.xml", func(t *testing.T, a DomainInfo) {
+			if !reflect.DeepEqual(a.LockState, NewBool(true)) || !reflect.DeepEqual(a.CanModify, NewBool(true)) || !reflect.DeepEqual(a.TransferAwayInProgress, NewBool(false)) {
+				t.Errorf("unexpected status %+v", a)
+			}
+			if a.AutoRenew != nil || a.ExpireDate != nil {
+				t.Errorf("all_info fields should be nil, got %+v", a)
+			}
+			if a.DomainSupports["supports_whois_privacy"] != "1" {
+				t.Errorf("unexpected DomainSupports %v", a.DomainSupports)
+			}
+		}},
+		{GetWhoisPrivacyState, "domain.get.whois_privacy_state.xml", func(t *testing.T, a DomainInfo) {
+			if a.State == nil || *a.State != "enabled" || !reflect.DeepEqual(a.Changeable, NewBool(true)) {
+				t.Errorf("unexpected whois privacy %+v", a)
+			}
+		}},
+		{GetList, "domain.get.list.xml", func(t *testing.T, a DomainInfo) {
+			if a.Page == nil || *a.Page != 1 || a.Total == nil || *a.Total != 2 || !reflect.DeepEqual(a.Remainder, NewBool(false)) || len(a.DomainList) != 2 {
+				t.Fatalf("unexpected list %+v", a)
+			}
+			want := DomainListItem{Domain: "example.net", ExpireDate: time.Date(2026, 11, 2, 8, 0, 0, 0, time.UTC)}
+			if !reflect.DeepEqual(a.DomainList[1], want) {
+				t.Errorf("unexpected item, want %+v, got %+v", want, a.DomainList[1])
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.t), func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			respXML := readFile(t, "testresponses/"+tt.file)
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				var req GetRequest
+				if err := FromXml(readBody(t, r), &req); err != nil {
+					t.Error(err)
+					return
+				}
+				if req.Domain != "example.com" || req.Attributes.Type != tt.t {
+					t.Errorf("unexpected request %+v", req)
+				}
+				fmt.Fprint(w, respXML)
+			})
+
+			resp, err := client.Domains.Get("example.com", tt.t)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, resp.Attributes)
+		})
+	}
+}
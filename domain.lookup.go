@@ -1,34 +1,39 @@
 package opensrs
 
+import "context"
+
 type LookupRequest struct {
 	BaseRequest
-	Attributes LookupRequestAttributes `json:"attributes"`
+	Attributes LookupRequestAttributes `opensrs:"attributes"`
 }
 
 type LookupRequestAttributes struct {
-	Domain  string `json:"domain"`
-	NoCache Bool   `json:"no_cache,omitempty"`
+	Domain string `opensrs:"domain"`
+	// NoCache is sent only when set, use NewBool(false) to send "0".
+	NoCache *Bool `opensrs:"no_cache,omitempty"`
 }
 
 type LookupResponse struct {
 	BaseResponse
-	Attributes LookupResponseAttributes `json:"attributes"`
+	Attributes LookupResponseAttributes `opensrs:"attributes"`
 }
 
 type LookupResponseAttributes struct {
-	EmailAvailable Bool   `json:"email_available"`
-	HasClaim       Bool   `json:"has_claim"`
-	NoService      Bool   `json:"noservice"`
-	PriceStatus    string `json:"price_status"`
-	Status         string `json:"status"`
-	Reason         string `json:"reason"`
+	EmailAvailable Bool   `opensrs:"email_available"`
+	HasClaim       Bool   `opensrs:"has_claim"`
+	NoService      Bool   `opensrs:"noservice"`
+	PriceStatus    string `opensrs:"price_status"`
+	Status         string `opensrs:"status"`
+	Reason         string `opensrs:"reason"`
 }
 
-type DomainsService struct {
-	Client *Client
+// Lookup checks whether a domain is available for registration.
+func (s *DomainsService) Lookup(attr LookupRequestAttributes) (*LookupResponse, error) {
+	return s.LookupContext(context.Background(), attr)
 }
 
-func (s *DomainsService) Lookup(attr LookupRequestAttributes) (*LookupResponse, error) {
+// LookupContext is like Lookup but aborts the call when ctx is done.
+func (s *DomainsService) LookupContext(ctx context.Context, attr LookupRequestAttributes) (*LookupResponse, error) {
 	opsResponse := LookupResponse{}
 
 	payload := LookupRequest{
@@ -39,7 +44,7 @@ func (s *DomainsService) Lookup(attr LookupRequestAttributes) (*LookupResponse,
 		},
 		Attributes: attr,
 	}
-	req, err := s.Client.NewRequest("POST", "", payload)
+	req, err := s.Client.NewRequestWithContext(ctx, "POST", "", payload)
 	if err != nil {
 		return nil, err
 	}
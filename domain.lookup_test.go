@@ -1,11 +1,14 @@
 package opensrs
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // https://domains.opensrs.guide/docs/lookup-domain-2#example-1
@@ -51,7 +54,7 @@ func TestLookupExample1a(t *testing.T) {
 
 	resp, err := client.Domains.Lookup(LookupRequestAttributes{
 		Domain:  "example.com",
-		NoCache: true,
+		NoCache: NewBool(true),
 	})
 
 	if err != nil {
@@ -73,6 +76,11 @@ func TestLookupExample1a(t *testing.T) {
 		},
 	}
 
+	if resp.Raw == nil || string(resp.Raw.XML) != respXML {
+		t.Errorf("want the response body in resp.Raw")
+	}
+	resp.Raw = nil
+
 	if !reflect.DeepEqual(resp, want) {
 		t.Errorf("lookup returned, got\n%+v,\nwant\n%+v", resp, want)
 	}
@@ -122,7 +130,7 @@ func TestLookupExample1b(t *testing.T) {
 
 	resp, err := client.Domains.Lookup(LookupRequestAttributes{
 		Domain:  "example.com",
-		NoCache: true,
+		NoCache: NewBool(true),
 	})
 
 	if err != nil {
@@ -144,6 +152,11 @@ func TestLookupExample1b(t *testing.T) {
 		},
 	}
 
+	if resp.Raw == nil || string(resp.Raw.XML) != respXML {
+		t.Errorf("want the response body in resp.Raw")
+	}
+	resp.Raw = nil
+
 	if !reflect.DeepEqual(resp, want) {
 		t.Errorf("lookup returned, got\n%+v,\nwant\n%+v", resp, want)
 	}
@@ -215,8 +228,51 @@ func TestLookupExample2(t *testing.T) {
 		},
 	}
 
+	if resp.Raw == nil || string(resp.Raw.XML) != respXML {
+		t.Errorf("want the response body in resp.Raw")
+	}
+	resp.Raw = nil
+
 	if !reflect.DeepEqual(resp, want) {
 		t.Errorf("lookup returned, got\n%+v,\nwant\n%+v", resp, want)
 	}
 
 }
+
+func TestLookupContextDeadline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Domains.LookupContext(ctx, LookupRequestAttributes{
+		Domain: "example.com",
+	})
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	var canceled CanceledError
+	if !errors.As(err, &canceled) {
+		t.Fatalf("want CanceledError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want context.DeadlineExceeded, got %v", err)
+	}
+
+	var apiErr ErrorResponse
+	if errors.As(err, &apiErr) {
+		t.Errorf("cancellation must not be reported as ErrorResponse")
+	}
+}
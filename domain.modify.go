@@ -0,0 +1,186 @@
+package opensrs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ModifyData is a change made by Modify. It is implemented by the Modify*
+// types below, one for each value of the data attribute.
+type ModifyData interface {
+	modifyData() string
+}
+
+// ModifyContactInfo replaces the contacts of a domain. Unset contacts are
+// left as they are.
+type ModifyContactInfo struct {
+	ContactSet ContactSet `opensrs:"contact_set"`
+	// ReportEmail sends the registrant a confirmation of the change.
+	ReportEmail *Bool `opensrs:"report_email,omitempty"`
+}
+
+// ModifyExpireAction sets what happens when a domain expires.
+type ModifyExpireAction struct {
+	AutoRenew Bool `opensrs:"auto_renew"`
+	LetExpire Bool `opensrs:"let_expire"`
+}
+
+// ModifyStatus locks or unlocks a domain against transfers.
+type ModifyStatus struct {
+	LockState Bool `opensrs:"lock_state"`
+}
+
+// WhoisPrivacyState is the requested state of WHOIS privacy.
+type WhoisPrivacyState string
+
+const (
+	WhoisPrivacyEnable  WhoisPrivacyState = "enable"
+	WhoisPrivacyDisable WhoisPrivacyState = "disable"
+)
+
+// ModifyWhoisPrivacy turns WHOIS privacy on or off.
+type ModifyWhoisPrivacy struct {
+	State WhoisPrivacyState `opensrs:"state"`
+}
+
+// ModifyDomainAuthInfo sets the transfer authorization code.
+type ModifyDomainAuthInfo struct {
+	DomainAuthInfo string `opensrs:"domain_auth_info"`
+}
+
+// ModifyForwardingEmail sets the address that mail for the domain is
+// forwarded to.
+type ModifyForwardingEmail struct {
+	ForwardingEmail string `opensrs:"forwarding_email"`
+}
+
+// ModifyIPSTag moves a .uk domain to another registrar by changing its IPS
+// tag.
+type ModifyIPSTag struct {
+	GainingRegistrar string `opensrs:"gaining_registrar"`
+	// ChangeTagAll moves all domains of the registrant with the same tag.
+	ChangeTagAll Bool `opensrs:"change_tag_all"`
+}
+
+// ModifyNameservers adds, removes or reorders the nameservers of a domain.
+type ModifyNameservers struct {
+	NameserverList []NameserverChange `opensrs:"nameserver_list"`
+}
+
+// NameserverAction is what ModifyNameservers does with a nameserver.
+type NameserverAction string
+
+const (
+	NameserverAdd    NameserverAction = "add"
+	NameserverRemove NameserverAction = "remove"
+	NameserverUpdate NameserverAction = "update"
+)
+
+// NameserverChange is an entry of ModifyNameservers.
+type NameserverChange struct {
+	Action    NameserverAction `opensrs:"action"`
+	Name      string           `opensrs:"name"`
+	SortOrder int              `opensrs:"sortorder,omitempty"`
+}
+
+func (ModifyContactInfo) modifyData() string     { return "contact_info" }
+func (ModifyExpireAction) modifyData() string    { return "expire_action" }
+func (ModifyStatus) modifyData() string          { return "status" }
+func (ModifyWhoisPrivacy) modifyData() string    { return "whois_privacy_state" }
+func (ModifyDomainAuthInfo) modifyData() string  { return "domain_auth_info" }
+func (ModifyForwardingEmail) modifyData() string { return "forwarding_email" }
+func (ModifyIPSTag) modifyData() string          { return "change_ips_tag" }
+func (ModifyNameservers) modifyData() string     { return "nameserver_list" }
+
+// ModifyRequest names the domain directly instead of through a cookie,
+// like GetRequest.
+type ModifyRequest struct {
+	BaseRequest
+	Domain     string `opensrs:"domain"`
+	Attributes Map    `opensrs:"attributes"`
+}
+
+type ModifyRequestAttributes struct {
+	Domain string
+	Change ModifyData
+	// AffectDomains applies the change to all domains in the same profile.
+	AffectDomains bool
+}
+
+type ModifyResponse struct {
+	BaseResponse
+	Attributes ModifyResponseAttributes `opensrs:"attributes"`
+}
+
+type ModifyResponseAttributes struct {
+	// Details holds the outcome for every affected domain, by name, when
+	// AffectDomains is set.
+	Details map[string]ModifyResult `opensrs:"details"`
+}
+
+type ModifyResult struct {
+	IsSuccess    Bool   `opensrs:"is_success"`
+	ResponseCode string `opensrs:"response_code"`
+	ResponseText string `opensrs:"response_text"`
+}
+
+// modifyAttributes flattens the change into the attributes of the request,
+// next to data and affect_domains.
+func modifyAttributes(attr ModifyRequestAttributes) (Map, error) {
+	if attr.Change == nil {
+		return nil, fmt.Errorf("opensrs: modify %s: no change given", attr.Domain)
+	}
+	enc, err := encodeValue(reflect.ValueOf(attr.Change))
+	if err != nil {
+		return nil, err
+	}
+	assoc, ok := enc.(DtAssoc)
+	if !ok {
+		return nil, fmt.Errorf("opensrs: cannot encode %T as modify attributes", attr.Change)
+	}
+	m := assoc.decode()
+	m["data"] = attr.Change.modifyData()
+	m["affect_domains"] = "0"
+	if attr.AffectDomains {
+		m["affect_domains"] = "1"
+	}
+	return m, nil
+}
+
+// Modify changes a setting of a domain, selected by the type of
+// attr.Change.
+func (s *DomainsService) Modify(attr ModifyRequestAttributes) (*ModifyResponse, error) {
+	return s.ModifyContext(context.Background(), attr)
+}
+
+// ModifyContext is like Modify but aborts the call when ctx is done.
+func (s *DomainsService) ModifyContext(ctx context.Context, attr ModifyRequestAttributes) (*ModifyResponse, error) {
+	opsResponse := ModifyResponse{}
+
+	attributes, err := modifyAttributes(attr)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := ModifyRequest{
+		BaseRequest: BaseRequest{
+			Action:   "MODIFY",
+			Object:   "DOMAIN",
+			Protocol: "XCP",
+		},
+		Domain:     attr.Domain,
+		Attributes: attributes,
+	}
+	req, err := s.Client.NewRequestWithContext(ctx, "POST", "", payload)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Client.Do(req, &opsResponse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &opsResponse, nil
+}
@@ -0,0 +1,178 @@
+package opensrs
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// https://domains.opensrs.guide/docs/modify-domain
+func TestModifyStatus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.modify.status.xml")
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body := readBody(t, r)
+
+		// Test request body
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="action">MODIFY</item><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="domain">example.com</item><item key="attributes"><dt_assoc><item key="affect_domains">0</item><item key="data">status</item><item key="lock_state">1</item></dt_assoc></item></dt_assoc></data_block></body></OPS_envelope>`
+		if string(body) != want {
+			t.Errorf("modify request, got\n%s,\nwant\n%s", body, want)
+		}
+
+		// Test req method
+		testMethod(t, r)
+
+		// Test authentication method
+		testAuth(t, r.Header, string(body))
+
+		fmt.Fprint(w, respXML)
+	})
+
+	resp, err := client.Domains.Modify(ModifyRequestAttributes{
+		Domain: "example.com",
+		Change: ModifyStatus{LockState: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.IsSuccess || resp.Attributes.Details != nil {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestModifyData(t *testing.T) {
+	tests := []struct {
+		change ModifyData
+		data   string
+		check  func(t *testing.T, a Map)
+	}{
+		{ModifyContactInfo{ContactSet: ContactSet{Owner: testContact}}, "contact_info", func(t *testing.T, a Map) {
+			if v, _ := a.GetString("contact_set/owner/email"); v != testContact.Email {
+				t.Errorf("unexpected owner email %q", v)
+			}
+			if _, ok := a.Get("contact_set/admin"); ok {
+				t.Errorf("unset admin contact was sent")
+			}
+			if _, ok := a.Get("report_email"); ok {
+				t.Errorf("unset report_email was sent")
+			}
+		}},
+		{ModifyExpireAction{AutoRenew: true}, "expire_action", func(t *testing.T, a Map) {
+			if a["auto_renew"] != "1" || a["let_expire"] != "0" {
+				t.Errorf("unexpected attributes %v", a)
+			}
+		}},
+		{ModifyWhoisPrivacy{State: WhoisPrivacyDisable}, "whois_privacy_state", func(t *testing.T, a Map) {
+			if a["state"] != "disable" {
+				t.Errorf("unexpected state %v", a["state"])
+			}
+		}},
+		{ModifyDomainAuthInfo{DomainAuthInfo: "s3cret"}, "domain_auth_info", func(t *testing.T, a Map) {
+			if a["domain_auth_info"] != "s3cret" {
+				t.Errorf("unexpected domain_auth_info %v", a["domain_auth_info"])
+			}
+		}},
+		{ModifyForwardingEmail{ForwardingEmail: "owner@example.net"}, "forwarding_email", func(t *testing.T, a Map) {
+			if a["forwarding_email"] != "owner@example.net" {
+				t.Errorf("unexpected forwarding_email %v", a["forwarding_email"])
+			}
+		}},
+		{ModifyIPSTag{GainingRegistrar: "TUCOWS-CA", ChangeTagAll: true}, "change_ips_tag", func(t *testing.T, a Map) {
+			if a["gaining_registrar"] != "TUCOWS-CA" || a["change_tag_all"] != "1" {
+				t.Errorf("unexpected attributes %v", a)
+			}
+		}},
+		{ModifyNameservers{NameserverList: []NameserverChange{
+			{Action: NameserverAdd, Name: "ns3.example.net", SortOrder: 3},
+			{Action: NameserverRemove, Name: "ns1.example.net"},
+		}}, "nameserver_list", func(t *testing.T, a Map) {
+			if v, _ := a.GetString("nameserver_list/0/action"); v != "add" {
+				t.Errorf("unexpected first action %q", v)
+			}
+			if v, _ := a.GetString("nameserver_list/1/name"); v != "ns1.example.net" {
+				t.Errorf("unexpected second name %q", v)
+			}
+			if _, ok := a.Get("nameserver_list/1/sortorder"); ok {
+				t.Errorf("unset sortorder was sent")
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			respXML := readFile(t, "testresponses/domain.modify.status.xml")
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				var req ModifyRequest
+				if err := FromXml(readBody(t, r), &req); err != nil {
+					t.Error(err)
+					return
+				}
+				if req.Domain != "example.com" || req.Attributes["data"] != tt.data {
+					t.Errorf("unexpected request %+v", req)
+				}
+				tt.check(t, req.Attributes)
+				fmt.Fprint(w, respXML)
+			})
+
+			if _, err := client.Domains.Modify(ModifyRequestAttributes{Domain: "example.com", Change: tt.change}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestModifyAffectDomains(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.modify.affect_domains.xml")
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var req ModifyRequest
+		if err := FromXml(readBody(t, r), &req); err != nil {
+			t.Error(err)
+			return
+		}
+		if req.Attributes["affect_domains"] != "1" {
+			t.Errorf("affect_domains not set: %v", req.Attributes)
+		}
+		fmt.Fprint(w, respXML)
+	})
+
+	resp, err := client.Domains.Modify(ModifyRequestAttributes{
+		Domain:        "example.com",
+		Change:        ModifyExpireAction{AutoRenew: true},
+		AffectDomains: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]ModifyResult{
+		"example.com": {IsSuccess: true, ResponseCode: "200", ResponseText: "Command successful"},
+		"example.net": {IsSuccess: false, ResponseCode: "435", ResponseText: "Request not allowed, domain is locked by the registry"},
+	}
+	if !reflect.DeepEqual(resp.Attributes.Details, want) {
+		t.Errorf("unexpected details, want %+v, got %+v", want, resp.Attributes.Details)
+	}
+}
+
+func TestModifyNoChange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent without a change")
+	})
+
+	if _, err := client.Domains.Modify(ModifyRequestAttributes{Domain: "example.com"}); err == nil {
+		t.Error("want an error without a change")
+	}
+}
@@ -1,27 +1,30 @@
 package opensrs
 
+import "context"
+
 // Response
 type NameSuggestResponse struct {
 	BaseResponse
-	IsSearchComplete Bool                          `json:"is_search_complete"`
-	SearchKey        string                        `json:"search_key"`
-	Attributes       NameSuggestResponseAttributes `json:"attributes"`
+	IsSearchComplete    Bool                          `opensrs:"is_search_completed"`
+	RequestResponseTime string                        `opensrs:"request_response_time"`
+	SearchKey           string                        `opensrs:"search_key"`
+	Attributes          NameSuggestResponseAttributes `opensrs:"attributes"`
 }
 
 type NameSuggestResponseAttributes struct {
-	Lookup                  NameSuggestItems                `json:"lookup"`
-	PersonalNames           NameSuggestItems                `json:"personal_names"`
-	Premium                 NameSuggestItems                `json:"premium"`
-	PremiumBrokeredTransfer PremiumBrokeredTransferResponse `json:"premium_brokered_transfer"`
-	PremiumMakeOffer        PremiumMakeOfferResponse        `json:"premium_make_offer"`
-	Suggestion              NameSuggestItems                `json:"suggestion"`
+	Lookup                  NameSuggestItems                `opensrs:"lookup"`
+	PersonalNames           NameSuggestItems                `opensrs:"personal_names"`
+	Premium                 NameSuggestItems                `opensrs:"premium"`
+	PremiumBrokeredTransfer PremiumBrokeredTransferResponse `opensrs:"premium_brokered_transfer"`
+	PremiumMakeOffer        PremiumMakeOfferResponse        `opensrs:"premium_make_offer"`
+	Suggestion              NameSuggestItems                `opensrs:"suggestion"`
 }
 
 type PremiumMakeOfferResponse struct {
-	Count        string            `json:"count"`
-	ResponseText string            `json:"response_text"`
-	ResponseCode string            `json:"response_code"`
-	Items        []NameSuggestItem `json:"items"`
+	Count        string            `opensrs:"count"`
+	ResponseText string            `opensrs:"response_text"`
+	ResponseCode string            `opensrs:"response_code"`
+	Items        []NameSuggestItem `opensrs:"items"`
 }
 
 type PremiumBrokeredTransferResponse struct {
@@ -29,69 +32,75 @@ type PremiumBrokeredTransferResponse struct {
 }
 
 type NameSuggestItems struct {
-	Count        string            `json:"count"`
-	ResponseText string            `json:"response_text"`
-	ResponseCode string            `json:"response_code"`
-	IsSuccess    Bool              `json:"is_success"`
-	Items        []NameSuggestItem `json:"items"`
+	Count        string            `opensrs:"count"`
+	ResponseText string            `opensrs:"response_text"`
+	ResponseCode string            `opensrs:"response_code"`
+	IsSuccess    Bool              `opensrs:"is_success"`
+	Items        []NameSuggestItem `opensrs:"items"`
 }
 
 type NameSuggestItem struct {
-	Domain             string `json:"domain"`
-	Price              string `json:"price"`
-	Status             string `json:"status"`
-	HasClaim           Bool   `json:"has_claim"`
-	Reason             string `json:"reason"`
-	ThirdPartyOfferUrl URL    `json:"third_party_offer_url"`
+	Domain             string `opensrs:"domain"`
+	Price              string `opensrs:"price"`
+	Status             string `opensrs:"status"`
+	HasClaim           Bool   `opensrs:"has_claim"`
+	Reason             string `opensrs:"reason"`
+	ThirdPartyOfferUrl URL    `opensrs:"third_party_offer_url"`
 }
 
 // Requests
 type NameSuggestRequest struct {
 	BaseRequest
-	Attributes NameSuggestRequestAttributes `json:"attributes"`
+	Attributes NameSuggestRequestAttributes `opensrs:"attributes"`
 }
 
 type NameSuggestRequestAttributes struct {
-	Languages          []string                   `json:"languages,omitempty"`
-	MaxWaitTime        string                     `json:"max_wait_time,omitempty"`
-	SearchKey          string                     `json:"search_key,omitempty"`
-	SearchString       string                     `json:"searchstring,omitempty"`
-	ServiceOverride    NameSuggestServiceOverride `json:"service_override,omitempty"`
-	Services           []string                   `json:"services,omitempty"`
-	SkipRegistryLookup bool                       `json:"skip_registry_lookup,omitempty"`
-	TLDs               []string                   `json:"tlds,omitempty"`
+	Languages          []string                    `opensrs:"languages,omitempty"`
+	MaxWaitTime        string                      `opensrs:"max_wait_time,omitempty"`
+	SearchKey          string                      `opensrs:"search_key,omitempty"`
+	SearchString       string                      `opensrs:"searchstring,omitempty"`
+	ServiceOverride    *NameSuggestServiceOverride `opensrs:"service_override,omitempty"`
+	Services           []string                    `opensrs:"services,omitempty"`
+	SkipRegistryLookup *Bool                       `opensrs:"skip_registry_lookup,omitempty"`
+	TLDs               []string                    `opensrs:"tlds,omitempty"`
 }
 
 type NameSuggestServiceOverride struct {
-	Lookup        NameSuggestLookup     `json:"lookup,omitempty"`
-	PersonalNames []string              `json:"personal_names,omitempty"`
-	Premium       NameSuggestPremium    `json:"premium,omitempty"`
-	Suggestion    NameSuggestSuggestion `json:"suggestion,omitempty"`
+	Lookup        *NameSuggestLookup     `opensrs:"lookup,omitempty"`
+	PersonalNames []string               `opensrs:"personal_names,omitempty"`
+	Premium       *NameSuggestPremium    `opensrs:"premium,omitempty"`
+	Suggestion    *NameSuggestSuggestion `opensrs:"suggestion,omitempty"`
 }
 
 type NameSuggestSuggestion struct {
-	Maximum  string   `json:"maximum,omitempty"`
-	PriceMax string   `json:"price_max,omitempty"`
-	PriceMin string   `json:"price_min,omitempty"`
-	TLDs     []string `json:"tlds,omitempty"`
+	Maximum  string   `opensrs:"maximum,omitempty"`
+	PriceMax string   `opensrs:"price_max,omitempty"`
+	PriceMin string   `opensrs:"price_min,omitempty"`
+	TLDs     []string `opensrs:"tlds,omitempty"`
 }
 
 type NameSuggestPremium struct {
-	Maximum  string   `json:"maximum,omitempty"`
-	PriceMax string   `json:"price_max,omitempty"`
-	PriceMin string   `json:"price_min,omitempty"`
-	TLDs     []string `json:"tlds,omitempty"`
+	Maximum  string   `opensrs:"maximum,omitempty"`
+	PriceMax string   `opensrs:"price_max,omitempty"`
+	PriceMin string   `opensrs:"price_min,omitempty"`
+	TLDs     []string `opensrs:"tlds,omitempty"`
 }
 
 type NameSuggestLookup struct {
-	Maximum    string   `json:"maximum,omitempty"`
-	PriceMax   string   `json:"price_max,omitempty"`
-	PriceMin   string   `json:"price_min,omitempty"`
-	TLDs       []string `json:"tlds,omitempty"`
-	NoCacheTld []string `json:"no_cache_tlds,omitempty"`
+	Maximum    string   `opensrs:"maximum,omitempty"`
+	PriceMax   string   `opensrs:"price_max,omitempty"`
+	PriceMin   string   `opensrs:"price_min,omitempty"`
+	TLDs       []string `opensrs:"tlds,omitempty"`
+	NoCacheTld []string `opensrs:"no_cache_tlds,omitempty"`
 }
 
+// NameSuggest returns lookup results and name suggestions for a search string.
 func (s *DomainsService) NameSuggest(attr NameSuggestRequestAttributes) (*NameSuggestResponse, error) {
+	return s.NameSuggestContext(context.Background(), attr)
+}
+
+// NameSuggestContext is like NameSuggest but aborts the call when ctx is done.
+func (s *DomainsService) NameSuggestContext(ctx context.Context, attr NameSuggestRequestAttributes) (*NameSuggestResponse, error) {
 	opsResponse := NameSuggestResponse{}
 
 	payload := NameSuggestRequest{
@@ -102,7 +111,7 @@ func (s *DomainsService) NameSuggest(attr NameSuggestRequestAttributes) (*NameSu
 		},
 		Attributes: attr,
 	}
-	req, err := s.Client.NewRequest("POST", "", payload)
+	req, err := s.Client.NewRequestWithContext(ctx, "POST", "", payload)
 	if err != nil {
 		return nil, err
 	}
@@ -196,12 +196,12 @@ func TestNameSuggestExample2(t *testing.T) {
 	resp, err := client.Domains.NameSuggest(NameSuggestRequestAttributes{
 		SearchString: "example@search.com",
 		Services:     []string{"lookup", "suggestion"},
-		ServiceOverride: NameSuggestServiceOverride{
-			Suggestion: NameSuggestSuggestion{
+		ServiceOverride: &NameSuggestServiceOverride{
+			Suggestion: &NameSuggestSuggestion{
 				TLDs:    []string{".com", ".org"},
 				Maximum: "25",
 			},
-			Lookup: NameSuggestLookup{
+			Lookup: &NameSuggestLookup{
 				TLDs:       []string{".com", ".info"},
 				NoCacheTld: []string{".com"},
 			},
@@ -276,7 +276,7 @@ func TestNameSuggestExample3(t *testing.T) {
 		}
 
 		// Test request body
-		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="searchstring">abc&amp;amp;d !</item><item key="service_override"><dt_assoc><item key="lookup"><dt_assoc></dt_assoc></item><item key="premium"><dt_assoc><item key="tlds"><dt_array><item key="0">.com</item><item key="1">.net</item></dt_array></item></dt_assoc></item><item key="suggestion"><dt_assoc></dt_assoc></item></dt_assoc></item><item key="services"><dt_array><item key="0">premium</item></dt_array></item></dt_assoc></item><item key="action">NAME_SUGGEST</item></dt_assoc></data_block></body></OPS_envelope>`
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="searchstring">abc&amp;amp;d !</item><item key="service_override"><dt_assoc><item key="premium"><dt_assoc><item key="tlds"><dt_array><item key="0">.com</item><item key="1">.net</item></dt_array></item></dt_assoc></item></dt_assoc></item><item key="services"><dt_array><item key="0">premium</item></dt_array></item></dt_assoc></item><item key="action">NAME_SUGGEST</item></dt_assoc></data_block></body></OPS_envelope>`
 		var wantReqXml NameSuggestRequest
 		err = FromXml([]byte(want), &wantReqXml)
 		if err != nil {
@@ -306,8 +306,8 @@ func TestNameSuggestExample3(t *testing.T) {
 	resp, err := client.Domains.NameSuggest(NameSuggestRequestAttributes{
 		SearchString: "abc&amp;d !",
 		Services:     []string{"premium"},
-		ServiceOverride: NameSuggestServiceOverride{
-			Premium: NameSuggestPremium{
+		ServiceOverride: &NameSuggestServiceOverride{
+			Premium: &NameSuggestPremium{
 				TLDs: []string{".com", ".net"},
 			},
 		},
@@ -397,15 +397,15 @@ func TestNameSuggestExample4(t *testing.T) {
 	resp, err := client.Domains.NameSuggest(NameSuggestRequestAttributes{
 		SearchString: "abc&amp;d",
 		Services:     []string{"lookup", "suggestion", "premium"},
-		ServiceOverride: NameSuggestServiceOverride{
-			Premium: NameSuggestPremium{
+		ServiceOverride: &NameSuggestServiceOverride{
+			Premium: &NameSuggestPremium{
 				TLDs: []string{".com"},
 			},
-			Suggestion: NameSuggestSuggestion{
+			Suggestion: &NameSuggestSuggestion{
 				TLDs:    []string{".com"},
 				Maximum: "10",
 			},
-			Lookup: NameSuggestLookup{
+			Lookup: &NameSuggestLookup{
 				TLDs: []string{".com"},
 			},
 		},
@@ -508,7 +508,7 @@ func TestNameSuggestExample5(t *testing.T) {
 		}
 
 		// Test request body
-		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="action">NAME_SUGGEST</item><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="services"><dt_array><item key="0">lookup</item><item key="1">suggestion</item></dt_array></item><item key="searchstring">smith</item><item key="service_override"><dt_assoc><item key="lookup"><dt_assoc><item key="tlds"><dt_array><item key="0">.com</item></dt_array></item></dt_assoc></item><item key="premium"><dt_assoc></dt_assoc></item><item key="suggestion"><dt_assoc><item key="maximum">10</item><item key="tlds"><dt_array><item key="0">.com</item></dt_array></item></dt_assoc></item></dt_assoc></item></dt_assoc></item></dt_assoc></data_block></body></OPS_envelope>`
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="action">NAME_SUGGEST</item><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="services"><dt_array><item key="0">lookup</item><item key="1">suggestion</item></dt_array></item><item key="searchstring">smith</item><item key="service_override"><dt_assoc><item key="lookup"><dt_assoc><item key="tlds"><dt_array><item key="0">.com</item></dt_array></item></dt_assoc></item><item key="suggestion"><dt_assoc><item key="maximum">10</item><item key="tlds"><dt_array><item key="0">.com</item></dt_array></item></dt_assoc></item></dt_assoc></item></dt_assoc></item></dt_assoc></data_block></body></OPS_envelope>`
 		var wantReqXml NameSuggestRequest
 		err = FromXml([]byte(want), &wantReqXml)
 		if err != nil {
@@ -538,12 +538,12 @@ func TestNameSuggestExample5(t *testing.T) {
 	resp, err := client.Domains.NameSuggest(NameSuggestRequestAttributes{
 		SearchString: "smith",
 		Services:     []string{"lookup", "suggestion"},
-		ServiceOverride: NameSuggestServiceOverride{
-			Suggestion: NameSuggestSuggestion{
+		ServiceOverride: &NameSuggestServiceOverride{
+			Suggestion: &NameSuggestSuggestion{
 				TLDs:    []string{".com"},
 				Maximum: "10",
 			},
-			Lookup: NameSuggestLookup{
+			Lookup: &NameSuggestLookup{
 				TLDs: []string{".com"},
 			},
 		},
@@ -621,7 +621,7 @@ func TestNameSuggestExample6(t *testing.T) {
 		}
 
 		// Test request body
-		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="action">NAME_SUGGEST</item><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="tlds"><dt_array><item key="0">.com</item><item key="1">.net</item><item key="2">.org</item><item key="3">in</item></dt_array></item><item key="languages"><dt_array><item key="0">en</item><item key="1">de</item><item key="2">it</item><item key="3">es</item></dt_array></item><item key="max_wait_time">0.4</item><item key="searchstring">search string</item><item key="service_override"><dt_assoc><item key="lookup"><dt_assoc></dt_assoc></item><item key="premium"><dt_assoc></dt_assoc></item><item key="suggestion"><dt_assoc></dt_assoc></item></dt_assoc></item><item key="services"><dt_array><item key="0">lookup</item><item key="1">suggestion</item><item key="2">premium</item><item key="3">personal_names</item></dt_array></item></dt_assoc></item></dt_assoc></data_block></body></OPS_envelope>`
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="action">NAME_SUGGEST</item><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="tlds"><dt_array><item key="0">.com</item><item key="1">.net</item><item key="2">.org</item><item key="3">in</item></dt_array></item><item key="languages"><dt_array><item key="0">en</item><item key="1">de</item><item key="2">it</item><item key="3">es</item></dt_array></item><item key="max_wait_time">0.4</item><item key="searchstring">search string</item><item key="services"><dt_array><item key="0">lookup</item><item key="1">suggestion</item><item key="2">premium</item><item key="3">personal_names</item></dt_array></item></dt_assoc></item></dt_assoc></data_block></body></OPS_envelope>`
 		var wantReqXml NameSuggestRequest
 		err = FromXml([]byte(want), &wantReqXml)
 		if err != nil {
@@ -898,7 +898,7 @@ func TestNameSuggestExample8(t *testing.T) {
 		}
 
 		// Test request body
-		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="attributes"><dt_assoc><item key="searchstring">computerstore</item><item key="service_override"><dt_assoc><item key="lookup"><dt_assoc></dt_assoc></item><item key="premium"><dt_assoc><item key="maximum">10</item><item key="price_max">10000</item><item key="price_min">100</item><item key="tlds"><dt_array><item key="0">.com</item><item key="1">.net</item></dt_array></item></dt_assoc></item><item key="suggestion"><dt_assoc></dt_assoc></item></dt_assoc></item><item key="services"><dt_array><item key="0">premium</item></dt_array></item></dt_assoc></item><item key="action">NAME_SUGGEST</item><item key="object">DOMAIN</item><item key="protocol">XCP</item></dt_assoc></data_block></body></OPS_envelope>`
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="attributes"><dt_assoc><item key="searchstring">computerstore</item><item key="service_override"><dt_assoc><item key="premium"><dt_assoc><item key="maximum">10</item><item key="price_max">10000</item><item key="price_min">100</item><item key="tlds"><dt_array><item key="0">.com</item><item key="1">.net</item></dt_array></item></dt_assoc></item></dt_assoc></item><item key="services"><dt_array><item key="0">premium</item></dt_array></item></dt_assoc></item><item key="action">NAME_SUGGEST</item><item key="object">DOMAIN</item><item key="protocol">XCP</item></dt_assoc></data_block></body></OPS_envelope>`
 		var wantReqXml NameSuggestRequest
 		err = FromXml([]byte(want), &wantReqXml)
 		if err != nil {
@@ -928,8 +928,8 @@ func TestNameSuggestExample8(t *testing.T) {
 	resp, err := client.Domains.NameSuggest(NameSuggestRequestAttributes{
 		SearchString: "computerstore",
 		Services:     []string{"premium"},
-		ServiceOverride: NameSuggestServiceOverride{
-			Premium: NameSuggestPremium{
+		ServiceOverride: &NameSuggestServiceOverride{
+			Premium: &NameSuggestPremium{
 				TLDs:     []string{".com", ".net"},
 				Maximum:  "10",
 				PriceMin: "100",
@@ -992,7 +992,7 @@ func TestNameSuggestExample9(t *testing.T) {
 		}
 
 		// Test request body
-		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="services"><dt_array><item key="0">lookup</item></dt_array></item><item key="tlds"><dt_array><item key="0">guru</item></dt_array></item><item key="searchstring">example</item><item key="service_override"><dt_assoc><item key="suggestion"><dt_assoc></dt_assoc></item><item key="lookup"><dt_assoc></dt_assoc></item><item key="premium"><dt_assoc></dt_assoc></item></dt_assoc></item></dt_assoc></item><item key="action">NAME_SUGGEST</item></dt_assoc></data_block></body></OPS_envelope>`
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="services"><dt_array><item key="0">lookup</item></dt_array></item><item key="tlds"><dt_array><item key="0">guru</item></dt_array></item><item key="searchstring">example</item></dt_assoc></item><item key="action">NAME_SUGGEST</item></dt_assoc></data_block></body></OPS_envelope>`
 		var wantReqXml NameSuggestRequest
 		err = FromXml([]byte(want), &wantReqXml)
 		if err != nil {
@@ -1079,7 +1079,7 @@ func TestNameSuggestExample10(t *testing.T) {
 		}
 
 		// Test request body
-		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="searchstring">testdomain</item><item key="service_override"><dt_assoc><item key="lookup"><dt_assoc></dt_assoc></item><item key="premium"><dt_assoc></dt_assoc></item><item key="suggestion"><dt_assoc></dt_assoc></item></dt_assoc></item><item key="services"><dt_array><item key="0">premium</item><item key="1">premium_make_offer</item><item key="2">premium_brokered_transfer</item><item key="3">lookup</item></dt_array></item><item key="tlds"><dt_array><item key="0">.com</item><item key="1">.net</item><item key="2">.org</item><item key="3">.de</item></dt_array></item></dt_assoc></item><item key="action">NAME_SUGGEST</item><item key="object">DOMAIN</item></dt_assoc></data_block></body></OPS_envelope>`
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="searchstring">testdomain</item><item key="services"><dt_array><item key="0">premium</item><item key="1">premium_make_offer</item><item key="2">premium_brokered_transfer</item><item key="3">lookup</item></dt_array></item><item key="tlds"><dt_array><item key="0">.com</item><item key="1">.net</item><item key="2">.org</item><item key="3">.de</item></dt_array></item></dt_assoc></item><item key="action">NAME_SUGGEST</item><item key="object">DOMAIN</item></dt_assoc></data_block></body></OPS_envelope>`
 		var wantReqXml NameSuggestRequest
 		err = FromXml([]byte(want), &wantReqXml)
 		if err != nil {
@@ -0,0 +1,107 @@
+package opensrs
+
+import "context"
+
+type RegisterRequest struct {
+	BaseRequest
+	Attributes RegisterRequestAttributes `opensrs:"attributes"`
+}
+
+// RegType is the kind of registration requested by SW_REGISTER.
+type RegType string
+
+const (
+	RegTypeNew      RegType = "new"
+	RegTypeTransfer RegType = "transfer"
+	RegTypeSunrise  RegType = "sunrise"
+	RegTypeLandrush RegType = "landrush"
+)
+
+// Handle tells OpenSRS whether to process an order right away or to save
+// it for later.
+type Handle string
+
+const (
+	HandleProcess Handle = "process"
+	HandleSave    Handle = "save"
+)
+
+type RegisterRequestAttributes struct {
+	Domain string `opensrs:"domain"`
+	// Period is the registration period in years.
+	Period  int     `opensrs:"period,omitempty"`
+	RegType RegType `opensrs:"reg_type"`
+	Handle  Handle  `opensrs:"handle,omitempty"`
+	// RegUsername and RegPassword are the credentials of the registrant's
+	// profile.
+	RegUsername string     `opensrs:"reg_username"`
+	RegPassword string     `opensrs:"reg_password"`
+	ContactSet  ContactSet `opensrs:"contact_set"`
+	// CustomNameservers is set by Register when NameserverList is not
+	// empty, otherwise the reseller's default nameservers are used.
+	CustomNameservers Bool         `opensrs:"custom_nameservers"`
+	NameserverList    []Nameserver `opensrs:"nameserver_list,omitempty"`
+	// CustomTechContact uses ContactSet.Tech instead of the reseller's
+	// tech contact.
+	CustomTechContact Bool  `opensrs:"custom_tech_contact"`
+	AutoRenew         *Bool `opensrs:"auto_renew,omitempty"`
+	WhoisPrivacy      *Bool `opensrs:"f_whois_privacy,omitempty"`
+	LockDomain        *Bool `opensrs:"f_lock_domain,omitempty"`
+	// AuthInfo is the transfer authorization code for RegTypeTransfer.
+	AuthInfo string `opensrs:"auth_info,omitempty"`
+	// TLDData holds registry specific attributes.
+	TLDData Map `opensrs:"tld_data,omitempty"`
+}
+
+type RegisterResponse struct {
+	BaseResponse
+	Attributes RegisterResponseAttributes `opensrs:"attributes"`
+}
+
+type RegisterResponseAttributes struct {
+	OrderID           string `opensrs:"id"`
+	AdminEmail        string `opensrs:"admin_email"`
+	RegistrationCode  string `opensrs:"registration_code"`
+	RegistrationText  string `opensrs:"registration_text"`
+	AsyncReason       string `opensrs:"async_reason"`
+	Error             string `opensrs:"error"`
+	ForcedPending     string `opensrs:"forced_pending"`
+	QueueRequestID    string `opensrs:"queue_request_id"`
+	TransferID        string `opensrs:"transfer_id"`
+	WhoisPrivacyState string `opensrs:"whois_privacy_state"`
+}
+
+// Register registers a new domain or submits a transfer, sunrise or
+// landrush order.
+func (s *DomainsService) Register(attr RegisterRequestAttributes) (*RegisterResponse, error) {
+	return s.RegisterContext(context.Background(), attr)
+}
+
+// RegisterContext is like Register but aborts the call when ctx is done.
+func (s *DomainsService) RegisterContext(ctx context.Context, attr RegisterRequestAttributes) (*RegisterResponse, error) {
+	opsResponse := RegisterResponse{}
+
+	if len(attr.NameserverList) > 0 {
+		attr.CustomNameservers = true
+	}
+
+	payload := RegisterRequest{
+		BaseRequest: BaseRequest{
+			Action:   "SW_REGISTER",
+			Object:   "DOMAIN",
+			Protocol: "XCP",
+		},
+		Attributes: attr,
+	}
+	req, err := s.Client.NewRequestWithContext(ctx, "POST", "", payload)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Client.Do(req, &opsResponse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &opsResponse, nil
+}
@@ -0,0 +1,158 @@
+package opensrs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testContact = &Contact{
+	FirstName:  "John",
+	LastName:   "Smith",
+	OrgName:    "Example Inc.",
+	Address1:   "32 Oak Street",
+	City:       "Santa Clara",
+	State:      "CA",
+	PostalCode: "90210",
+	Country:    "US",
+	Phone:      "+1.4165550123",
+	Email:      "jsmith@example.com",
+	Lang:       "EN",
+}
+
+// https://domains.opensrs.guide/docs/sw_register-domain-
+func TestRegisterExample1(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.sw_register.example1.xml")
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body := readBody(t, r)
+
+		// Test request body
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="action">SW_REGISTER</item><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="domain">example.com</item><item key="period">1</item><item key="reg_type">new</item><item key="handle">process</item><item key="reg_username">jsmith</item><item key="reg_password">s3cret</item><item key="contact_set"><dt_assoc><item key="owner"><dt_assoc><item key="first_name">John</item><item key="last_name">Smith</item><item key="org_name">Example Inc.</item><item key="address1">32 Oak Street</item><item key="city">Santa Clara</item><item key="state">CA</item><item key="postal_code">90210</item><item key="country">US</item><item key="phone">+1.4165550123</item><item key="email">jsmith@example.com</item><item key="lang">EN</item></dt_assoc></item><item key="admin"><dt_assoc><item key="first_name">John</item><item key="last_name">Smith</item><item key="org_name">Example Inc.</item><item key="address1">32 Oak Street</item><item key="city">Santa Clara</item><item key="state">CA</item><item key="postal_code">90210</item><item key="country">US</item><item key="phone">+1.4165550123</item><item key="email">jsmith@example.com</item><item key="lang">EN</item></dt_assoc></item><item key="billing"><dt_assoc><item key="first_name">John</item><item key="last_name">Smith</item><item key="org_name">Example Inc.</item><item key="address1">32 Oak Street</item><item key="city">Santa Clara</item><item key="state">CA</item><item key="postal_code">90210</item><item key="country">US</item><item key="phone">+1.4165550123</item><item key="email">jsmith@example.com</item><item key="lang">EN</item></dt_assoc></item></dt_assoc></item><item key="custom_nameservers">1</item><item key="nameserver_list"><dt_array><item key="0"><dt_assoc><item key="name">ns1.systemdns.com</item><item key="sortorder">1</item></dt_assoc></item><item key="1"><dt_assoc><item key="name">ns2.systemdns.com</item><item key="sortorder">2</item></dt_assoc></item></dt_array></item><item key="custom_tech_contact">0</item><item key="auto_renew">0</item><item key="f_whois_privacy">1</item></dt_assoc></item></dt_assoc></data_block></body></OPS_envelope>`
+		var wantReqXml RegisterRequest
+		err := FromXml([]byte(want), &wantReqXml)
+		if err != nil {
+			t.Error("error unmarshalling \"wanted request\" : ", err.Error())
+			return
+		}
+
+		var gotReqXml RegisterRequest
+		err = FromXml(body, &gotReqXml)
+		if err != nil {
+			t.Error("error unmarshalling \"got request\":  ", err.Error())
+			return
+		}
+
+		if !reflect.DeepEqual(wantReqXml, gotReqXml) {
+			t.Errorf("register request, got\n%+v,\nwant\n%+v", gotReqXml, wantReqXml)
+		}
+
+		// Test req method
+		testMethod(t, r)
+
+		// Test authentication method
+		testAuth(t, r.Header, string(body))
+
+		fmt.Fprint(w, respXML)
+	})
+
+	resp, err := client.Domains.Register(RegisterRequestAttributes{
+		Domain:      "example.com",
+		Period:      1,
+		RegType:     RegTypeNew,
+		Handle:      HandleProcess,
+		RegUsername: "jsmith",
+		RegPassword: "s3cret",
+		ContactSet: ContactSet{
+			Owner:   testContact,
+			Admin:   testContact,
+			Billing: testContact,
+		},
+		NameserverList: []Nameserver{
+			{Name: "ns1.systemdns.com", SortOrder: 1},
+			{Name: "ns2.systemdns.com", SortOrder: 2},
+		},
+		AutoRenew:    NewBool(false),
+		WhoisPrivacy: NewBool(true),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &RegisterResponse{
+		BaseResponse: BaseResponse{
+			Action:       "REPLY",
+			Object:       "DOMAIN",
+			Protocol:     "XCP",
+			ResponseCode: "200",
+			IsSuccess:    true,
+			ResponseText: "Domain registration successfully completed",
+		},
+		Attributes: RegisterResponseAttributes{
+			OrderID:           "3735281",
+			AdminEmail:        "jsmith@example.com",
+			RegistrationCode:  "200",
+			RegistrationText:  "Domain registration successfully completed\nWhois Privacy successfully enabled",
+			WhoisPrivacyState: "enabled",
+		},
+	}
+
+	resp.Raw = nil
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("register returned, got\n%+v,\nwant\n%+v", resp, want)
+	}
+}
+
+func TestRegisterTransfer(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.sw_register.example2.xml")
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var req RegisterRequest
+		if err := FromXml(readBody(t, r), &req); err != nil {
+			t.Error(err)
+			return
+		}
+		if req.Attributes.RegType != RegTypeTransfer || req.Attributes.AuthInfo != "abc123" {
+			t.Errorf("unexpected attributes %+v", req.Attributes)
+		}
+		if req.Attributes.CustomNameservers {
+			t.Errorf("custom_nameservers should not be set without a nameserver list")
+		}
+		fmt.Fprint(w, respXML)
+	})
+
+	resp, err := client.Domains.Register(RegisterRequestAttributes{
+		Domain:     "example.com",
+		RegType:    RegTypeTransfer,
+		AuthInfo:   "abc123",
+		ContactSet: ContactSet{Owner: testContact},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attributes.OrderID != "3735299" || resp.Attributes.TransferID != "38154" {
+		t.Errorf("unexpected attributes %+v", resp.Attributes)
+	}
+}
+
+func TestRegisterTaken(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, failedResponseXML, "485", "Domain taken")
+	})
+
+	_, err := client.Domains.Register(RegisterRequestAttributes{Domain: "example.com", RegType: RegTypeNew})
+	if !errors.Is(err, ErrDomainTaken) {
+		t.Errorf("want ErrDomainTaken, got %v", err)
+	}
+}
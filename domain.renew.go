@@ -0,0 +1,119 @@
+package opensrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type RenewRequest struct {
+	BaseRequest
+	Attributes RenewRequestAttributes `opensrs:"attributes"`
+}
+
+type RenewRequestAttributes struct {
+	Domain string `opensrs:"domain"`
+	Period int    `opensrs:"period"`
+	// CurrentExpirationYear guards against renewing twice: OpenSRS refuses
+	// the order unless it matches the year the domain expires. It is
+	// required; take it from the state the renewal decision was based on,
+	// not from a fresh GET, or a retried order renews the domain again.
+	CurrentExpirationYear int    `opensrs:"currentexpirationyear"`
+	AutoRenew             *Bool  `opensrs:"auto_renew,omitempty"`
+	Handle                Handle `opensrs:"handle,omitempty"`
+	// ParkPage is "Y" or "N".
+	ParkPage string `opensrs:"f_parkp,omitempty"`
+}
+
+type RenewResponse struct {
+	BaseResponse
+	Attributes RenewResponseAttributes `opensrs:"attributes"`
+}
+
+type RenewResponseAttributes struct {
+	OrderID                    string    `opensrs:"order_id"`
+	ID                         string    `opensrs:"id"`
+	QueueRequestID             string    `opensrs:"queue_request_id"`
+	AdminEmail                 string    `opensrs:"admin_email"`
+	AutoRenew                  Bool      `opensrs:"auto_renew"`
+	RegistrationExpirationDate time.Time `opensrs:"registration expiration date"`
+}
+
+// renewRefusedCode is the response code of a refused RENEW. OpenSRS uses
+// it for a currentexpirationyear that does not match, but also for other
+// refusals, so Renew confirms the mismatch before reporting it.
+const renewRefusedCode = "480"
+
+// ExpirationYearMismatchError is returned by Renew when Year is not the
+// year the domain currently expires, usually because it was renewed
+// already. Current is the year it expires according to GET. It matches
+// ErrExpirationYearMismatch, and Err is the ErrorResponse.
+type ExpirationYearMismatchError struct {
+	Domain  string
+	Year    int
+	Current int
+	Err     error
+}
+
+func (e ExpirationYearMismatchError) Error() string {
+	return fmt.Sprintf("opensrs: %s expires in %d, not %d: %v", e.Domain, e.Current, e.Year, e.Err)
+}
+
+func (e ExpirationYearMismatchError) Unwrap() error {
+	return e.Err
+}
+
+func (e ExpirationYearMismatchError) Is(target error) bool {
+	return target == ErrExpirationYearMismatch
+}
+
+// Renew renews a domain for attr.Period years.
+func (s *DomainsService) Renew(attr RenewRequestAttributes) (*RenewResponse, error) {
+	return s.RenewContext(context.Background(), attr)
+}
+
+// RenewContext is like Renew but aborts the call when ctx is done.
+func (s *DomainsService) RenewContext(ctx context.Context, attr RenewRequestAttributes) (*RenewResponse, error) {
+	opsResponse := RenewResponse{}
+
+	if attr.CurrentExpirationYear == 0 {
+		return nil, fmt.Errorf("opensrs: renew %s: CurrentExpirationYear is required", attr.Domain)
+	}
+
+	payload := RenewRequest{
+		BaseRequest: BaseRequest{
+			Action:   "RENEW",
+			Object:   "DOMAIN",
+			Protocol: "XCP",
+		},
+		Attributes: attr,
+	}
+	req, err := s.Client.NewRequestWithContext(ctx, "POST", "", payload)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Client.Do(req, &opsResponse)
+
+	var e ErrorResponse
+	if errors.As(err, &e) && e.OpenSRSResponse != nil && e.OpenSRSResponse.ResponseCode == renewRefusedCode {
+		if current, ok := s.expirationYear(ctx, attr.Domain); ok && current != attr.CurrentExpirationYear {
+			return nil, ExpirationYearMismatchError{Domain: attr.Domain, Year: attr.CurrentExpirationYear, Current: current, Err: err}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &opsResponse, nil
+}
+
+// expirationYear returns the year domain expires, or false when it cannot
+// be read.
+func (s *DomainsService) expirationYear(ctx context.Context, domain string) (int, bool) {
+	info, err := s.GetContext(ctx, domain, GetAllInfo)
+	if err != nil || info.Attributes.ExpireDate == nil || info.Attributes.ExpireDate.IsZero() {
+		return 0, false
+	}
+	return info.Attributes.ExpireDate.Year(), true
+}
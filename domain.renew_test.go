@@ -0,0 +1,146 @@
+package opensrs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// https://domains.opensrs.guide/docs/renew-domain
+func TestRenewExample1(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.renew.example1.xml")
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body := readBody(t, r)
+
+		// Test request body
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="action">RENEW</item><item key="object">DOMAIN</item><item key="protocol">XCP</item><item key="attributes"><dt_assoc><item key="domain">example.com</item><item key="period">2</item><item key="currentexpirationyear">2027</item><item key="auto_renew">0</item><item key="handle">process</item><item key="f_parkp">N</item></dt_assoc></item></dt_assoc></data_block></body></OPS_envelope>`
+		if string(body) != want {
+			t.Errorf("renew request, got\n%s,\nwant\n%s", body, want)
+		}
+
+		// Test req method
+		testMethod(t, r)
+
+		// Test authentication method
+		testAuth(t, r.Header, string(body))
+
+		fmt.Fprint(w, respXML)
+	})
+
+	resp, err := client.Domains.Renew(RenewRequestAttributes{
+		Domain:                "example.com",
+		Period:                2,
+		CurrentExpirationYear: 2027,
+		AutoRenew:             NewBool(false),
+		Handle:                HandleProcess,
+		ParkPage:              "N",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := RenewResponseAttributes{
+		OrderID:                    "3735332",
+		ID:                         "3735332",
+		AdminEmail:                 "jsmith@example.com",
+		RegistrationExpirationDate: time.Date(2029, 6, 29, 14, 41, 26, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(resp.Attributes, want) {
+		t.Errorf("renew returned, got\n%+v,\nwant\n%+v", resp.Attributes, want)
+	}
+}
+
+func TestRenewRequiresExpirationYear(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent without currentexpirationyear")
+	})
+
+	if _, err := client.Domains.Renew(RenewRequestAttributes{Domain: "example.com", Period: 1}); err == nil {
+		t.Error("want an error without CurrentExpirationYear")
+	}
+}
+
+// refusedRenew answers RENEW with a 480 and GET with the all_info fixture,
+// whose domain expires in 2027, moved to year.
+func refusedRenew(t *testing.T, year string) {
+	getXML := strings.Replace(readFile(t, "testresponses/domain.get.all_info.xml"), "<item key=\"expiredate\">2027", "<item key=\"expiredate\">"+year, 1)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var req RenewRequest
+		if err := FromXml(readBody(t, r), &req); err != nil {
+			t.Error(err)
+			return
+		}
+		switch req.Action {
+		case "GET":
+			fmt.Fprint(w, getXML)
+		case "RENEW":
+			fmt.Fprintf(w, failedResponseXML, "480", "Request is not allowed")
+		}
+	})
+}
+
+func TestRenewExpirationYearMismatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	refusedRenew(t, "2029")
+
+	_, err := client.Domains.Renew(RenewRequestAttributes{Domain: "example.com", Period: 1, CurrentExpirationYear: 2027})
+
+	var e ExpirationYearMismatchError
+	if !errors.As(err, &e) {
+		t.Fatalf("want ExpirationYearMismatchError, got %T %v", err, err)
+	}
+	if e.Domain != "example.com" || e.Year != 2027 || e.Current != 2029 {
+		t.Errorf("unexpected error %+v", e)
+	}
+	if !errors.Is(err, ErrExpirationYearMismatch) || !errors.Is(err, ErrNotAllowed) || !errors.Is(err, ErrCommandFailed) {
+		t.Errorf("want ErrExpirationYearMismatch, ErrNotAllowed and ErrCommandFailed, got %v", err)
+	}
+}
+
+func TestRenewRefusedForOtherReason(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// The year matches, so the 480 is about something else.
+	refusedRenew(t, "2027")
+
+	_, err := client.Domains.Renew(RenewRequestAttributes{Domain: "example.com", Period: 1, CurrentExpirationYear: 2027})
+
+	var e ExpirationYearMismatchError
+	if errors.As(err, &e) || errors.Is(err, ErrExpirationYearMismatch) {
+		t.Errorf("a refusal with a matching year is not a mismatch, got %v", err)
+	}
+	if !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("want ErrNotAllowed, got %v", err)
+	}
+}
+
+func TestRenewOtherFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, failedResponseXML, "465", "Invalid period")
+	})
+
+	_, err := client.Domains.Renew(RenewRequestAttributes{Domain: "example.com", Period: 11, CurrentExpirationYear: 2027})
+	if errors.Is(err, ErrExpirationYearMismatch) {
+		t.Errorf("only code 480 is a year mismatch, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidData) {
+		t.Errorf("want ErrInvalidData, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package opensrs
+
+import "context"
+
+// Domains is the domain API of the client. Depend on it rather than on
+// *DomainsService to substitute a mock in tests, such as
+// opensrstest.MockDomains.
+type Domains interface {
+	Get(domain string, t GetType) (*GetResponse, error)
+	GetContext(ctx context.Context, domain string, t GetType) (*GetResponse, error)
+	Lookup(attr LookupRequestAttributes) (*LookupResponse, error)
+	LookupContext(ctx context.Context, attr LookupRequestAttributes) (*LookupResponse, error)
+	Modify(attr ModifyRequestAttributes) (*ModifyResponse, error)
+	ModifyContext(ctx context.Context, attr ModifyRequestAttributes) (*ModifyResponse, error)
+	NameSuggest(attr NameSuggestRequestAttributes) (*NameSuggestResponse, error)
+	NameSuggestContext(ctx context.Context, attr NameSuggestRequestAttributes) (*NameSuggestResponse, error)
+	Register(attr RegisterRequestAttributes) (*RegisterResponse, error)
+	RegisterContext(ctx context.Context, attr RegisterRequestAttributes) (*RegisterResponse, error)
+	Renew(attr RenewRequestAttributes) (*RenewResponse, error)
+	RenewContext(ctx context.Context, attr RenewRequestAttributes) (*RenewResponse, error)
+}
+
+var _ Domains = (*DomainsService)(nil)
+
+// DomainsService implements Domains on top of a Client.
+type DomainsService struct {
+	Client *Client
+}
@@ -1,10 +1,74 @@
 package opensrs
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
+// Errors reported by OpenSRS. They are matched against the response_code
+// (and for a few generic codes, the response_text) of a failed command and
+// can be tested with errors.Is on the error returned by any call.
+var (
+	// ErrCommandFailed matches every unsuccessful OpenSRS response,
+	// including those that also match one of the more specific errors.
+	ErrCommandFailed     = errors.New("opensrs: command failed")
+	ErrAuthentication    = errors.New("opensrs: authentication failed")
+	ErrIPNotWhitelisted  = errors.New("opensrs: ip address not whitelisted")
+	ErrDomainTaken       = errors.New("opensrs: domain taken")
+	ErrInsufficientFunds = errors.New("opensrs: insufficient funds")
+	ErrInvalidData       = errors.New("opensrs: invalid data")
+	ErrNotAllowed        = errors.New("opensrs: command not allowed")
+	// ErrExpirationYearMismatch is matched by the error Renew returns when
+	// currentexpirationyear is not the year the domain expires, see
+	// ExpirationYearMismatchError.
+	ErrExpirationYearMismatch = errors.New("opensrs: expiration year does not match")
+
+	// ErrUnexpectedStatus is wrapped when the HTTP status is not 2xx.
+	ErrUnexpectedStatus = errors.New("opensrs: unexpected http status")
+)
+
+var responseCodeErrors = map[string]error{
+	"400": ErrAuthentication,
+	"401": ErrAuthentication,
+	"415": ErrAuthentication,
+	"221": ErrDomainTaken,
+	"485": ErrDomainTaken,
+	"435": ErrNotAllowed,
+	"480": ErrNotAllowed,
+	"487": ErrNotAllowed,
+	"436": ErrInvalidData,
+	"460": ErrInvalidData,
+	"465": ErrInvalidData,
+	"541": ErrInvalidData,
+}
+
+var responseTextErrors = []struct {
+	substr string
+	err    error
+}{
+	{"whitelist", ErrIPNotWhitelisted},
+	{"insufficient funds", ErrInsufficientFunds},
+	{"insufficient balance", ErrInsufficientFunds},
+	{"not enough funds", ErrInsufficientFunds},
+}
+
+// responseError maps an unsuccessful OpenSRS response to one of the
+// sentinel errors above, falling back to ErrCommandFailed.
+func responseError(r *BaseResponse) error {
+	text := strings.ToLower(r.ResponseText)
+	for _, t := range responseTextErrors {
+		if strings.Contains(text, t.substr) {
+			return t.err
+		}
+	}
+	if err, ok := responseCodeErrors[r.ResponseCode]; ok {
+		return err
+	}
+	return ErrCommandFailed
+}
+
 type ErrorResponse struct {
 	Err             error
 	HttpResponse    *http.Response
@@ -26,3 +90,31 @@ func (e ErrorResponse) Error() string {
 	}
 	return msg
 }
+
+// Unwrap returns the underlying transport, HTTP or OpenSRS error.
+func (e ErrorResponse) Unwrap() error {
+	return e.Err
+}
+
+// Is reports every OpenSRS failure as ErrCommandFailed, in addition to the
+// more specific error held in Err.
+func (e ErrorResponse) Is(target error) bool {
+	return target == ErrCommandFailed && e.OpenSRSResponse != nil
+}
+
+// CanceledError is returned when a call is abandoned because its context was
+// canceled or its deadline passed before OpenSRS answered. It is never used
+// for failures reported by the API itself, see ErrorResponse for those.
+type CanceledError struct {
+	Err error
+}
+
+func (e CanceledError) Error() string {
+	return "opensrs: request canceled: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying context error, so errors.Is(err,
+// context.DeadlineExceeded) and errors.Is(err, context.Canceled) work.
+func (e CanceledError) Unwrap() error {
+	return e.Err
+}
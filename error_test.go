@@ -0,0 +1,93 @@
+package opensrs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const failedResponseXML = `<?xml version='1.0' encoding="UTF-8" standalone="no" ?>
+<!DOCTYPE OPS_envelope SYSTEM "ops.dtd">
+<OPS_envelope>
+    <header>
+        <version>0.9</version>
+    </header>
+    <body>
+        <data_block>
+            <dt_assoc>
+                <item key="action">REPLY</item>
+                <item key="object">DOMAIN</item>
+                <item key="protocol">XCP</item>
+                <item key="is_success">0</item>
+                <item key="response_code">%s</item>
+                <item key="response_text">%s</item>
+            </dt_assoc>
+        </data_block>
+    </body>
+</OPS_envelope>`
+
+func TestResponseCodeErrors(t *testing.T) {
+	tests := []struct {
+		code string
+		text string
+		want error
+	}{
+		{"415", "Authentication failed", ErrAuthentication},
+		{"400", "Authentication Error.", ErrAuthentication},
+		{"400", "Your IP address is not whitelisted", ErrIPNotWhitelisted},
+		{"485", "Domain taken", ErrDomainTaken},
+		{"465", "Invalid attribute value", ErrInvalidData},
+		{"486", "Insufficient funds to complete the order", ErrInsufficientFunds},
+		{"999", "Something unexpected", ErrCommandFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+" "+tt.text, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, failedResponseXML, tt.code, tt.text)
+			})
+
+			_, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, err)
+			}
+			if !errors.Is(err, ErrCommandFailed) {
+				t.Errorf("want error to match ErrCommandFailed, got %v", err)
+			}
+
+			var e ErrorResponse
+			if !errors.As(err, &e) {
+				t.Fatalf("want ErrorResponse, got %T", err)
+			}
+			if e.OpenSRSResponse == nil || e.OpenSRSResponse.ResponseCode != tt.code {
+				t.Errorf("unexpected OpenSRSResponse %+v", e.OpenSRSResponse)
+			}
+		})
+	}
+}
+
+func TestUnexpectedHTTPStatus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+
+	_, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	if !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("want ErrUnexpectedStatus, got %v", err)
+	}
+	if errors.Is(err, ErrCommandFailed) {
+		t.Errorf("http failure must not match ErrCommandFailed")
+	}
+
+	var e ErrorResponse
+	if !errors.As(err, &e) || e.HttpResponse == nil || e.HttpResponse.StatusCode != http.StatusBadGateway {
+		t.Errorf("want ErrorResponse carrying the 502 response, got %#v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package opensrs
+
+import "context"
+
+type executeRequest struct {
+	BaseRequest
+	Attributes interface{} `opensrs:"attributes,omitempty"`
+}
+
+// Execute sends any XCP command, including those without a typed wrapper in
+// this package. attributes is encoded like the attributes of the typed
+// requests, so it may be a Map or a tagged struct, and may be nil.
+//
+// The whole decoded data block is returned as a Map together with its
+// BaseResponse. Both are also returned when OpenSRS reports a failure, so
+// the caller can inspect the reply.
+func (c *Client) Execute(ctx context.Context, object, action string, attributes interface{}) (Map, *BaseResponse, error) {
+	payload := executeRequest{
+		BaseRequest: BaseRequest{
+			Action:   action,
+			Object:   object,
+			Protocol: "XCP",
+		},
+		Attributes: attributes,
+	}
+	req, err := c.NewRequestWithContext(ctx, "POST", "", payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := Map{}
+	call, err := c.doCall(req, &m)
+	if err != nil && len(m) == 0 && call.parsed != nil {
+		call.parsed.decode(&m)
+	}
+	return m, call.Response, err
+}
@@ -0,0 +1,90 @@
+package opensrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// https://domains.opensrs.guide/docs/get_balance
+func TestExecuteGetBalance(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/balance.get_balance.example1.xml")
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body := readBody(t, r)
+
+		want := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc><item key="action">GET_BALANCE</item><item key="object">BALANCE</item><item key="protocol">XCP</item></dt_assoc></data_block></body></OPS_envelope>`
+		var wantReq, gotReq Map
+		if err := FromXml([]byte(want), &wantReq); err != nil {
+			t.Error("error unmarshalling \"wanted request\" : ", err.Error())
+			return
+		}
+		if err := FromXml(body, &gotReq); err != nil {
+			t.Error("error unmarshalling \"got request\":  ", err.Error())
+			return
+		}
+		if !reflect.DeepEqual(wantReq, gotReq) {
+			t.Errorf("request, got\n%+v,\nwant\n%+v", gotReq, wantReq)
+		}
+
+		testMethod(t, r)
+		testAuth(t, r.Header, string(body))
+
+		fmt.Fprint(w, respXML)
+	})
+
+	m, base, err := client.Execute(context.Background(), "BALANCE", "GET_BALANCE", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if base.ResponseCode != "200" || base.IsSuccess != true || base.Object != "BALANCE" {
+		t.Errorf("unexpected base response %+v", base)
+	}
+
+	attrs, ok := m["attributes"].(Map)
+	if !ok {
+		t.Fatalf("want attributes map, got %T", m["attributes"])
+	}
+	if attrs["balance"] != "8549.18" || attrs["hold_balance"] != "1676.05" {
+		t.Errorf("unexpected attributes %+v", attrs)
+	}
+}
+
+func TestExecuteSendsAttributes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var req Map
+		if err := FromXml(readBody(t, r), &req); err != nil {
+			t.Error(err)
+			return
+		}
+		attrs := req["attributes"].(Map)
+		if attrs["domain"] != "example.com" || attrs["type"] != "status" {
+			t.Errorf("unexpected attributes %+v", attrs)
+		}
+		fmt.Fprintf(w, failedResponseXML, "415", "Authentication failed")
+	})
+
+	m, base, err := client.Execute(context.Background(), "DOMAIN", "GET", Map{
+		"domain": "example.com",
+		"type":   "status",
+	})
+	if !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("want ErrAuthentication, got %v", err)
+	}
+	if base == nil || base.ResponseCode != "415" {
+		t.Errorf("want base response on failure, got %+v", base)
+	}
+	if m["response_code"] != "415" {
+		t.Errorf("want decoded map on failure, got %+v", m)
+	}
+}
@@ -0,0 +1,224 @@
+package opensrs
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testrequests/")
+
+const goldenReplyXML = `<?xml version='1.0' encoding="UTF-8" standalone="no" ?>
+<!DOCTYPE OPS_envelope SYSTEM "ops.dtd">
+<OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc>
+<item key="action">REPLY</item><item key="is_success">1</item><item key="response_code">200</item>
+</dt_assoc></data_block></body></OPS_envelope>`
+
+// goldenRequests lists one call per supported command. The exact request
+// each one sends is pinned in testrequests/<name>.xml.
+var goldenRequests = []struct {
+	name string
+	call func(c *Client) error
+}{
+	{"domain.lookup.example1", func(c *Client) error {
+		_, err := c.Domains.Lookup(LookupRequestAttributes{Domain: "example.com", NoCache: NewBool(true)})
+		return err
+	}},
+	{"domain.lookup.example2", func(c *Client) error {
+		_, err := c.Domains.Lookup(LookupRequestAttributes{Domain: "example.guru"})
+		return err
+	}},
+	{"domain.lookup.no_cache_false", func(c *Client) error {
+		_, err := c.Domains.Lookup(LookupRequestAttributes{Domain: "example.com", NoCache: NewBool(false)})
+		return err
+	}},
+	{"domain.namesuggest.example1", func(c *Client) error {
+		_, err := c.Domains.NameSuggest(NameSuggestRequestAttributes{
+			Services:     []string{"lookup", "suggestion", "premium", "personal_names"},
+			SearchString: "search string",
+			Languages:    []string{"en", "de", "it", "es"},
+			TLDs:         []string{".com", ".net", ".org"},
+		})
+		return err
+	}},
+	{"domain.namesuggest.example2", func(c *Client) error {
+		_, err := c.Domains.NameSuggest(NameSuggestRequestAttributes{
+			SearchString: "example@search.com",
+			Services:     []string{"lookup", "suggestion"},
+			ServiceOverride: &NameSuggestServiceOverride{
+				Suggestion: &NameSuggestSuggestion{
+					TLDs:    []string{".com", ".org"},
+					Maximum: "25",
+				},
+				Lookup: &NameSuggestLookup{
+					TLDs:       []string{".com", ".info"},
+					NoCacheTld: []string{".com"},
+				},
+			},
+		})
+		return err
+	}},
+	{"domain.namesuggest.example7", func(c *Client) error {
+		_, err := c.Domains.NameSuggest(NameSuggestRequestAttributes{
+			SearchKey:   "vgL2FeBzZ8JuS5lIluIEYhDc7Vg",
+			MaxWaitTime: "0.7",
+		})
+		return err
+	}},
+	{"domain.sw_register.example1", func(c *Client) error {
+		_, err := c.Domains.Register(RegisterRequestAttributes{
+			Domain:      "example.com",
+			Period:      2,
+			RegType:     RegTypeNew,
+			Handle:      HandleProcess,
+			RegUsername: "jsmith",
+			RegPassword: "s3cret",
+			ContactSet: ContactSet{
+				Owner:   &Contact{FirstName: "John", LastName: "Smith", Country: "US", Email: "jsmith@example.com"},
+				Admin:   &Contact{FirstName: "John", LastName: "Smith", Country: "US", Email: "jsmith@example.com"},
+				Billing: &Contact{FirstName: "John", LastName: "Smith", Country: "US", Email: "jsmith@example.com"},
+			},
+			NameserverList: []Nameserver{
+				{Name: "ns1.systemdns.com", SortOrder: 1},
+				{Name: "ns2.systemdns.com", SortOrder: 2},
+			},
+			AutoRenew:    NewBool(true),
+			WhoisPrivacy: NewBool(false),
+		})
+		return err
+	}},
+	{"domain.get.all_info", func(c *Client) error {
+		_, err := c.Domains.Get("example.com", GetAllInfo)
+		return err
+	}},
+	{"domain.modify.contact_info", func(c *Client) error {
+		_, err := c.Domains.Modify(ModifyRequestAttributes{
+			Domain: "example.com",
+			Change: ModifyContactInfo{
+				ContactSet: ContactSet{
+					Owner: &Contact{FirstName: "John", LastName: "Smith", Country: "US", Email: "jsmith@example.com"},
+					Admin: &Contact{FirstName: "John", LastName: "Smith", Country: "US", Email: "jsmith@example.com"},
+				},
+				ReportEmail: NewBool(false),
+			},
+			AffectDomains: true,
+		})
+		return err
+	}},
+	{"domain.modify.domain_auth_info", func(c *Client) error {
+		_, err := c.Domains.Modify(ModifyRequestAttributes{
+			Domain: "example.com",
+			Change: ModifyDomainAuthInfo{DomainAuthInfo: "Ex4mple-Auth"},
+		})
+		return err
+	}},
+	{"domain.modify.expire_action", func(c *Client) error {
+		_, err := c.Domains.Modify(ModifyRequestAttributes{
+			Domain: "example.com",
+			Change: ModifyExpireAction{AutoRenew: true, LetExpire: false},
+		})
+		return err
+	}},
+	{"domain.modify.nameserver_list", func(c *Client) error {
+		_, err := c.Domains.Modify(ModifyRequestAttributes{
+			Domain: "example.com",
+			Change: ModifyNameservers{NameserverList: []NameserverChange{
+				{Action: NameserverAdd, Name: "ns3.example.net", SortOrder: 3},
+				{Action: NameserverRemove, Name: "ns1.example.net"},
+			}},
+		})
+		return err
+	}},
+	{"domain.modify.status", func(c *Client) error {
+		_, err := c.Domains.Modify(ModifyRequestAttributes{
+			Domain: "example.com",
+			Change: ModifyStatus{LockState: true},
+		})
+		return err
+	}},
+	{"domain.renew.example1", func(c *Client) error {
+		_, err := c.Domains.Renew(RenewRequestAttributes{
+			Domain:                "example.com",
+			Period:                2,
+			CurrentExpirationYear: 2027,
+			AutoRenew:             NewBool(false),
+			Handle:                HandleProcess,
+			ParkPage:              "N",
+		})
+		return err
+	}},
+	{"execute.get_domain", func(c *Client) error {
+		_, _, err := c.Execute(context.Background(), "DOMAIN", "GET", Map{
+			"domain":       "example.com",
+			"type":         "all_info",
+			"reg_username": "user",
+			"reg_password": "secret",
+		})
+		return err
+	}},
+}
+
+func TestGoldenRequests(t *testing.T) {
+	for _, tt := range goldenRequests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			var got []byte
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				got = readBody(t, r)
+				fmt.Fprint(w, goldenReplyXML)
+			})
+
+			if err := tt.call(client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			path := filepath.Join("testrequests", tt.name+".xml")
+			if *update {
+				if err := ioutil.WriteFile(path, append(got, '\n'), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want := bytes.TrimRight([]byte(readFile(t, path)), "\n")
+			if !bytes.Equal(got, want) {
+				t.Errorf("request does not match %s, got\n%s\nwant\n%s", path, got, want)
+			}
+		})
+	}
+}
+
+func TestEncodingIsDeterministic(t *testing.T) {
+	payload := executeRequest{
+		BaseRequest: BaseRequest{Action: "SW_REGISTER", Object: "DOMAIN", Protocol: "XCP"},
+		Attributes: Map{
+			"domain": "example.com", "period": 1, "reg_type": "new",
+			"contact_set": Map{
+				"owner": Map{"first_name": "A", "last_name": "B", "email": "a@example.com"},
+				"admin": Map{"first_name": "C", "last_name": "D", "email": "c@example.com"},
+			},
+			"nameserver_list": []Map{{"name": "ns1.example.com", "sortorder": 1}},
+		},
+	}
+
+	c := NewClient(apiUser, apiKey)
+	first, err := c.NewRequest("POST", "", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		req, err := c.NewRequest("POST", "", payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.Header.Get("X-Signature") != first.Header.Get("X-Signature") {
+			t.Fatalf("signature changed between identical requests")
+		}
+	}
+}
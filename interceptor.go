@@ -0,0 +1,50 @@
+package opensrs
+
+import (
+	"context"
+	"net/http"
+)
+
+// Call describes one OPS call as it passes through the interceptors.
+type Call struct {
+	Action string
+	Object string
+	// Request is the signed HTTP request. Interceptors may add headers, but
+	// changing the body invalidates the signature.
+	Request *http.Request
+	// Envelope is the encoded XML request, including the XML header.
+	Envelope []byte
+
+	// HttpResponse, ResponseBody and Response are filled in once OpenSRS
+	// has answered. The body of HttpResponse has already been consumed.
+	// An interceptor may replace ResponseBody; Response is then parsed
+	// from the new body, and success and errors follow it.
+	HttpResponse *http.Response
+	ResponseBody []byte
+	Response     *BaseResponse
+
+	parsed *response
+}
+
+// Invoker performs a call.
+type Invoker func(ctx context.Context, call *Call) error
+
+// Interceptor wraps a call. It normally calls next and may inspect or
+// change the call before and after it. An interceptor can short-circuit
+// the call by returning without calling next: returning an error fails the
+// call, while setting call.ResponseBody (for example from a cache) and
+// returning nil answers it.
+type Interceptor func(ctx context.Context, call *Call, next Invoker) error
+
+// chain wraps final in interceptors, the first interceptor being the
+// outermost.
+func chain(interceptors []Interceptor, final Invoker) Invoker {
+	next := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		ic, n := interceptors[i], next
+		next = func(ctx context.Context, call *Call) error {
+			return ic(ctx, call, n)
+		}
+	}
+	return next
+}
@@ -0,0 +1,144 @@
+package opensrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestInterceptorsSeeCall(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.lookup.example1a.xml")
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Trace-Id"); got != "trace-1" {
+			t.Errorf("want injected header, got %q", got)
+		}
+		fmt.Fprint(w, respXML)
+	})
+
+	var order []string
+	client.Interceptors = []Interceptor{
+		func(ctx context.Context, call *Call, next Invoker) error {
+			order = append(order, "outer before")
+			err := next(ctx, call)
+			order = append(order, "outer after")
+			return err
+		},
+		func(ctx context.Context, call *Call, next Invoker) error {
+			order = append(order, "inner before")
+			if call.Action != "LOOKUP" || call.Object != "DOMAIN" {
+				t.Errorf("unexpected action/object %s/%s", call.Action, call.Object)
+			}
+			if !strings.Contains(string(call.Envelope), `<item key="domain">example.com</item>`) {
+				t.Errorf("unexpected envelope %s", call.Envelope)
+			}
+			call.Request.Header.Set("X-Trace-Id", "trace-1")
+
+			err := next(ctx, call)
+
+			if call.HttpResponse == nil || call.HttpResponse.StatusCode != http.StatusOK {
+				t.Errorf("unexpected http response %+v", call.HttpResponse)
+			}
+			if call.Response == nil || call.Response.ResponseCode != "210" {
+				t.Errorf("unexpected response %+v", call.Response)
+			}
+			order = append(order, "inner after")
+			return err
+		},
+	}
+
+	resp, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attributes.Status != "available" {
+		t.Errorf("unexpected status %q", resp.Attributes.Status)
+	}
+
+	want := []string{"outer before", "inner before", "inner after", "outer after"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("want order %v, got %v", want, order)
+	}
+}
+
+func TestInterceptorShortCircuitWithCachedResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("server must not be called")
+	})
+
+	cached := []byte(readFile(t, "testresponses/domain.lookup.example1b.xml"))
+	client.Interceptors = []Interceptor{
+		func(ctx context.Context, call *Call, next Invoker) error {
+			call.ResponseBody = cached
+			return nil
+		},
+	}
+
+	resp, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attributes.Status != "taken" || resp.ResponseCode != "211" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestInterceptorReplacesResponseBody(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.lookup.example1a.xml")
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, respXML)
+	})
+
+	client.Interceptors = []Interceptor{
+		func(ctx context.Context, call *Call, next Invoker) error {
+			if err := next(ctx, call); err != nil {
+				return err
+			}
+			call.ResponseBody = []byte(fmt.Sprintf(failedResponseXML, "415", "Authentication failed"))
+			return nil
+		},
+	}
+
+	_, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("want the replaced body's ErrAuthentication, got %v", err)
+	}
+}
+
+func TestInterceptorBlocksCall(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("server must not be called")
+	})
+
+	errBlocked := errors.New("blocked")
+	client.Interceptors = []Interceptor{
+		func(ctx context.Context, call *Call, next Invoker) error {
+			if !IsReadOnlyAction(call.Action) {
+				return errBlocked
+			}
+			return next(ctx, call)
+		},
+	}
+
+	req, err := client.NewRequest("POST", "", BaseRequest{Action: "SW_REGISTER", Object: "DOMAIN", Protocol: "XCP"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Do(req, &BaseResponse{}); err != errBlocked {
+		t.Errorf("want errBlocked, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package opensrs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// Field is a key/value pair attached to a log entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger receives the client's log entries. Entries carry fields such as
+// action, object, response_code, latency and correlation_id. Request and
+// response data is redacted before it reaches the logger.
+type Logger interface {
+	Log(level LogLevel, msg string, fields ...Field)
+}
+
+// NewStdLogger returns a Logger that writes entries at or above min to l
+// as "LEVEL msg key=value ...".
+func NewStdLogger(l *log.Logger, min LogLevel) Logger {
+	return &stdLogger{l: l, min: min}
+}
+
+type stdLogger struct {
+	l   *log.Logger
+	min LogLevel
+}
+
+func (s *stdLogger) Log(level LogLevel, msg string, fields ...Field) {
+	if level < s.min {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(level.String())
+	b.WriteString(" ")
+	b.WriteString(msg)
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%q", f.Key, fmt.Sprint(f.Value))
+	}
+	s.l.Print(b.String())
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Log(LogLevel, string, ...Field) {}
+
+// logger returns the configured Logger. When none is set, Debug turns on a
+// standard logger at debug level.
+func (c *Client) logger() Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	if c.Debug {
+		return NewStdLogger(log.New(log.Writer(), "", log.LstdFlags), LevelDebug)
+	}
+	return nopLogger{}
+}
+
+// redactor returns the configured Redactor or the default one.
+func (c *Client) redactor() *Redactor {
+	if c.Redactor != nil {
+		return c.Redactor
+	}
+	return defaultRedactor
+}
+
+const redacted = "[REDACTED]"
+
+// Redactor masks secrets before they are logged.
+type Redactor struct {
+	// Headers lists the HTTP headers whose values are masked.
+	Headers []string
+	// Items lists the keys of OPS items whose values are masked,
+	// e.g. "reg_password" or "email".
+	Items []string
+
+	// mu guards the pattern compiled for the items it was built from.
+	mu      sync.Mutex
+	itemsRe string
+	re      *regexp.Regexp
+}
+
+var defaultRedactor = DefaultRedactor()
+
+// DefaultRedactor masks the request signature, passwords, auth codes and
+// contact email addresses.
+func DefaultRedactor() *Redactor {
+	return &Redactor{
+		Headers: []string{"X-Signature", "Authorization", "Cookie"},
+		Items: []string{
+			"password",
+			"reg_password",
+			"auth_info",
+			"domain_auth_info",
+			"cookie",
+			"email",
+			"admin_email",
+		},
+	}
+}
+
+// RedactHeader returns a copy of h with the configured headers masked.
+func (r *Redactor) RedactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range r.Headers {
+		if out.Get(name) != "" {
+			out.Set(name, redacted)
+		}
+	}
+	return out
+}
+
+// RedactXML returns a copy of an OPS envelope with the values of the
+// configured items masked.
+func (r *Redactor) RedactXML(b []byte) []byte {
+	if len(r.Items) == 0 {
+		return b
+	}
+	return r.itemPattern().ReplaceAll(b, []byte("${1}"+redacted+"${2}"))
+}
+
+// itemPattern returns the pattern matching the configured items. It is
+// compiled again only when Items changed since the last call.
+func (r *Redactor) itemPattern() *regexp.Regexp {
+	keys := make([]string, len(r.Items))
+	for i, k := range r.Items {
+		keys[i] = regexp.QuoteMeta(k)
+	}
+	alt := strings.Join(keys, "|")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.re == nil || r.itemsRe != alt {
+		r.re = regexp.MustCompile(`(<item\s+key=["'](?:` + alt + `)["']\s*>)[^<]*(</item>)`)
+		r.itemsRe = alt
+	}
+	return r.re
+}
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context whose calls are logged with id. Calls
+// made without one get a random correlation ID.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID stored in ctx, if any.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
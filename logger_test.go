@@ -0,0 +1,131 @@
+package opensrs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type logEntry struct {
+	level  LogLevel
+	msg    string
+	fields map[string]interface{}
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) Log(level LogLevel, msg string, fields ...Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	m := make(map[string]interface{})
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+	l.entries = append(l.entries, logEntry{level, msg, m})
+}
+
+func TestLoggerFieldsAndRedaction(t *testing.T) {
+	setup()
+	defer teardown()
+
+	logger := &recordingLogger{}
+	client.Logger = logger
+
+	respXML := readFile(t, "testresponses/domain.lookup.example1a.xml")
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, respXML)
+	})
+
+	ctx := WithCorrelationID(context.Background(), "abc123")
+	_, err := client.Domains.LookupContext(ctx, LookupRequestAttributes{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(logger.entries) == 0 {
+		t.Fatal("nothing was logged")
+	}
+
+	var done *logEntry
+	for i, e := range logger.entries {
+		if got := e.fields["correlation_id"]; got != "abc123" {
+			t.Errorf("%q: want correlation_id abc123, got %v", e.msg, got)
+		}
+		if e.fields["action"] != "LOOKUP" || e.fields["object"] != "DOMAIN" {
+			t.Errorf("%q: unexpected action/object %v/%v", e.msg, e.fields["action"], e.fields["object"])
+		}
+		if e.msg == "opensrs call succeeded" {
+			done = &logger.entries[i]
+		}
+		if h, ok := e.fields["header"].(http.Header); ok && h.Get("X-Signature") != redacted {
+			t.Errorf("X-Signature not redacted: %q", h.Get("X-Signature"))
+		}
+	}
+
+	if done == nil {
+		t.Fatal("no completion entry logged")
+	}
+	if done.level != LevelInfo {
+		t.Errorf("want completion at info level, got %v", done.level)
+	}
+	if _, ok := done.fields["latency"]; !ok {
+		t.Errorf("completion entry has no latency")
+	}
+}
+
+func TestRedactXML(t *testing.T) {
+	in := `<dt_assoc><item key="reg_username">user</item><item key="reg_password">s3cret</item>` +
+		`<item key='email'>owner@example.com</item><item key="auth_info">EPP-CODE</item></dt_assoc>`
+
+	got := string(DefaultRedactor().RedactXML([]byte(in)))
+
+	for _, secret := range []string{"s3cret", "owner@example.com", "EPP-CODE"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("%q not redacted in %s", secret, got)
+		}
+	}
+	if !strings.Contains(got, `<item key="reg_username">user</item>`) {
+		t.Errorf("unrelated item was changed: %s", got)
+	}
+}
+
+func TestRedactXMLCompilesOnce(t *testing.T) {
+	r := &Redactor{Items: []string{"reg_password"}}
+	in := []byte(`<item key="reg_password">s3cret</item><item key="email">owner@example.com</item>`)
+
+	r.RedactXML(in)
+	re := r.re
+	r.RedactXML(in)
+	if r.re != re {
+		t.Error("pattern compiled again for the same items")
+	}
+
+	r.Items = append(r.Items, "email")
+	if got := string(r.RedactXML(in)); strings.Contains(got, "owner@example.com") {
+		t.Errorf("added item not redacted in %s", got)
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	r := &Redactor{Headers: []string{"X-Signature"}}
+	h := http.Header{}
+	h.Set("X-Signature", "deadbeef")
+	h.Set("X-Username", "reseller")
+
+	got := r.RedactHeader(h)
+	if got.Get("X-Signature") != redacted {
+		t.Errorf("X-Signature not redacted")
+	}
+	if got.Get("X-Username") != "reseller" {
+		t.Errorf("X-Username changed")
+	}
+	if h.Get("X-Signature") != "deadbeef" {
+		t.Errorf("original header was modified")
+	}
+}
@@ -4,37 +4,54 @@ package opensrs
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"html"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const (
 	Version          = "0.0.1"
 	defaultBaseURL   = "https://rr-n1-tor.opensrs.net:55443"
+	testBaseURL      = "https://horizon.opensrs.net:55443"
 	defaultUserAgent = "opensrs-go/" + Version
 	xmlHeader        = "<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'>"
 )
 
 type BaseRequest struct {
-	Action   string `json:"action"`
-	Object   string `json:"object"`
-	Protocol string `json:"protocol"`
+	Action   string `opensrs:"action"`
+	Object   string `opensrs:"object"`
+	Protocol string `opensrs:"protocol"`
+}
+
+// baseRequest lets the client recognise any payload that embeds BaseRequest.
+func (r BaseRequest) baseRequest() BaseRequest {
+	return r
+}
+
+type baseRequestKey struct{}
+
+// requestBase returns the BaseRequest recorded by NewRequestWithContext.
+func requestBase(req *http.Request) BaseRequest {
+	b, _ := req.Context().Value(baseRequestKey{}).(BaseRequest)
+	return b
 }
 
 type BaseResponse struct {
-	Action       string `json:"action"`
-	Object       string `json:"object"`
-	Protocol     string `json:"protocol"`
-	IsSuccess    Bool   `json:"is_success"`
-	ResponseCode string `json:"response_code"`
-	ResponseText string `json:"response_text"`
+	Action       string `opensrs:"action"`
+	Object       string `opensrs:"object"`
+	Protocol     string `opensrs:"protocol"`
+	IsSuccess    Bool   `opensrs:"is_success"`
+	ResponseCode string `opensrs:"response_code"`
+	ResponseText string `opensrs:"response_text"`
+	// Raw holds the response body and its untyped form.
+	Raw *RawResponse `opensrs:"-"`
 }
 
 type Client struct {
@@ -42,23 +59,70 @@ type Client struct {
 	ApiKey           string
 	ResellerUsername string
 	BaseURL          string
-	Debug            bool
-	Domains          *DomainsService
+	UserAgent        string
+	// Debug logs every call to the standard logger when Logger is nil.
+	Debug bool
+	// Logger receives structured log entries for every call.
+	Logger Logger
+	// Redactor masks secrets before anything is logged, nil means
+	// DefaultRedactor.
+	Redactor *Redactor
+	// RetryPolicy decides which failed calls are attempted again, nil
+	// disables retries.
+	RetryPolicy *RetryPolicy
+	// RateLimiter throttles outgoing calls, nil disables throttling.
+	RateLimiter *RateLimiter
+	// Interceptors wrap every call, the first one being the outermost.
+	Interceptors []Interceptor
+	// Strict reports response items that the response type cannot
+	// represent, see StrictMode.
+	Strict  StrictMode
+	Domains *DomainsService
+
+	// timeout is set by WithTimeout and applied once all options ran.
+	timeout *time.Duration
 }
 
-func NewClient(ResellerUsername, ApiKey string) *Client {
+// NewClient returns a client for the live environment, configured further
+// by opts.
+func NewClient(ResellerUsername, ApiKey string, opts ...Option) *Client {
 	c := &Client{
 		ApiKey:           ApiKey,
 		ResellerUsername: ResellerUsername,
 		HttpClient:       &http.Client{},
 		BaseURL:          defaultBaseURL,
+		UserAgent:        defaultUserAgent,
+		RetryPolicy:      DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.HttpClient == nil {
+		c.HttpClient = &http.Client{}
+	}
+	if c.timeout != nil {
+		hc := *c.HttpClient
+		hc.Timeout = *c.timeout
+		c.HttpClient = &hc
 	}
 	c.Domains = &DomainsService{Client: c}
 	return c
 }
 
+// NewRequest builds a signed OPS request for payload. It is equivalent to
+// NewRequestWithContext with context.Background().
 func (c *Client) NewRequest(method, path string, payload interface{}) (*http.Request, error) {
-	url := c.BaseURL + path
+	return c.NewRequestWithContext(context.Background(), method, path, payload)
+}
+
+// NewRequestWithContext builds a signed OPS request for payload that is
+// bound to ctx, so canceling ctx aborts the HTTP exchange.
+func (c *Client) NewRequestWithContext(ctx context.Context, method, path string, payload interface{}) (*http.Request, error) {
+	url := strings.TrimRight(c.BaseURL, "/") + "/" + strings.TrimLeft(path, "/")
+
+	if p, ok := payload.(interface{ baseRequest() BaseRequest }); ok {
+		ctx = context.WithValue(ctx, baseRequestKey{}, p.baseRequest())
+	}
 
 	body := new(bytes.Buffer)
 	if payload != nil {
@@ -68,13 +132,9 @@ func (c *Client) NewRequest(method, path string, payload interface{}) (*http.Req
 		}
 		body = bytes.NewBuffer([]byte(xmlHeader))
 		body.Write(xml)
-
-		if c.Debug {
-			log.Printf("Request sent: %s\n", xmlHeader+string(xml))
-		}
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -84,63 +144,239 @@ func (c *Client) NewRequest(method, path string, payload interface{}) (*http.Req
 	req.Header.Set("Content-Type", "text/xml")
 	req.Header.Set("X-Username", c.ResellerUsername)
 	req.Header.Set("X-Signature", signature)
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 
 	return req, nil
 }
 
+// Do sends req through c.Interceptors and decodes the response into obj,
+// retrying according to c.RetryPolicy.
 func (c *Client) Do(req *http.Request, obj interface{}) error {
-	if c.Debug {
-		log.Printf("Executing request (%v): %#v", req.URL, req)
+	_, err := c.doCall(req, obj)
+	return err
+}
+
+// doCall is Do, but also returns the call so callers can reach the decoded
+// BaseResponse.
+func (c *Client) doCall(req *http.Request, obj interface{}) (*Call, error) {
+	ctx := req.Context()
+	if CorrelationID(ctx) == "" {
+		ctx = WithCorrelationID(ctx, newCorrelationID())
+		req = req.WithContext(ctx)
 	}
-	e := ErrorResponse{}
 
-	resp, err := c.HttpClient.Do(req)
+	base := requestBase(req)
+	call := &Call{
+		Action:  base.Action,
+		Object:  base.Object,
+		Request: req,
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			call.Envelope, _ = ioutil.ReadAll(body)
+		}
+	}
+
+	err := chain(c.Interceptors, c.invoke)(ctx, call)
 	if err != nil {
-		e.Err = err
-		return e
+		return call, err
 	}
-	defer resp.Body.Close()
 
-	e.HttpResponse = resp
+	e := ErrorResponse{HttpResponse: call.HttpResponse}
+	if len(call.ResponseBody) > 0 && !call.parsed.parsedFor(call.ResponseBody) {
+		parsed, err := parseReply(call.ResponseBody)
+		if err != nil {
+			e.Err = err
+			return call, e
+		}
+		// The body was set or replaced by an interceptor, so the base
+		// fields of any earlier reply no longer apply.
+		call.parsed = parsed
+		call.Response = &parsed.base
+	}
 
-	if c.Debug {
-		log.Printf("Response received: %#v\n", resp)
+	var strictErr *StrictDecodeError
+	if obj != nil && call.parsed != nil {
+		if err := c.decodeResponse(ctx, call, obj); err != nil {
+			if !errors.As(err, &strictErr) {
+				e.Err = err
+				return call, e
+			}
+		}
 	}
 
-	if resp.StatusCode < 200 && resp.StatusCode > 299 {
-		e.Err = errors.New("unexpected return code")
-		return e
+	if call.Response != nil && call.Response.IsSuccess != true {
+		e.OpenSRSResponse = call.Response
+		e.Err = responseError(call.Response)
+		return call, e
 	}
 
-	if obj != nil {
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			e.Err = err
-			return e
+	if strictErr != nil {
+		e.Err = strictErr
+		return call, e
+	}
+
+	return call, nil
+}
+
+// decodeResponse decodes the parsed reply of call into obj according to
+// c.Strict. Issues are logged in StrictWarn mode and returned as a
+// *StrictDecodeError in StrictError mode. Calls that change state are
+// never failed for them, since the change has been made already and a
+// caller retrying on the error would make it twice; their issues are
+// logged in StrictError mode too.
+func (c *Client) decodeResponse(ctx context.Context, call *Call, obj interface{}) error {
+	if c.Strict == StrictOff {
+		return call.parsed.decode(obj)
+	}
+	err := call.parsed.decodeStrict(obj)
+	var strictErr *StrictDecodeError
+	warn := c.Strict == StrictWarn || !IsReadOnlyAction(call.Action)
+	if warn && errors.As(err, &strictErr) {
+		for _, issue := range strictErr.Issues {
+			c.logger().Log(LevelWarn, "opensrs response item not decoded",
+				Field{"action", call.Action},
+				Field{"object", call.Object},
+				Field{"correlation_id", CorrelationID(ctx)},
+				Field{"path", issue.Path},
+				Field{"reason", issue.Reason},
+			)
 		}
-		if c.Debug {
-			log.Printf("Resp body received:\n##########\n%s\n##########\n", string(b))
+		return nil
+	}
+	return err
+}
+
+// invoke sends call.Request, retrying it according to c.RetryPolicy.
+func (c *Client) invoke(ctx context.Context, call *Call) error {
+	logger := c.logger()
+	fields := []Field{
+		{"action", call.Action},
+		{"object", call.Object},
+		{"correlation_id", CorrelationID(ctx)},
+	}
+
+	req := call.Request
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := c.send(req, call, logger, fields)
+		latency := time.Since(start)
+		if err == nil {
+			logger.Log(LevelInfo, "opensrs call succeeded", append(fields,
+				Field{"attempt", attempt},
+				Field{"latency", latency},
+				Field{"response_code", call.Response.ResponseCode},
+			)...)
+			return nil
 		}
 
-		err = FromXml(b, obj)
-		if err != nil {
-			e.Err = err
-			return e
+		a := RetryAttempt{
+			Action:       call.Action,
+			Object:       call.Object,
+			Attempt:      attempt,
+			Err:          err,
+			HttpResponse: call.HttpResponse,
+			Response:     call.Response,
+		}
+		failure := append(fields,
+			Field{"attempt", attempt},
+			Field{"latency", latency},
+			Field{"error", err},
+		)
+		if a.Response != nil {
+			failure = append(failure, Field{"response_code", a.Response.ResponseCode})
+		}
+
+		if !c.RetryPolicy.retry(a) || !rewindable(call.Request) {
+			logger.Log(LevelError, "opensrs call failed", failure...)
+			return err
 		}
 
-		oResp := BaseResponse{}
-		err = FromXml(b, &oResp)
+		logger.Log(LevelWarn, "opensrs call failed, retrying", failure...)
+		if werr := c.RetryPolicy.wait(ctx, attempt); werr != nil {
+			return werr
+		}
+		req, err = rewindRequest(call.Request)
 		if err != nil {
-			e.Err = err
-			return e
+			return ErrorResponse{Err: err}
 		}
+	}
+}
+
+// send makes a single HTTP attempt and records the outcome in call.
+func (c *Client) send(req *http.Request, call *Call, logger Logger, fields []Field) error {
+	call.HttpResponse, call.ResponseBody, call.Response, call.parsed = nil, nil, nil, nil
+
+	release, err := c.RateLimiter.acquire(req.Context(), call.Action)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	redactor := c.redactor()
+	if _, ok := logger.(nopLogger); !ok {
+		logger.Log(LevelDebug, "opensrs request", append(fields,
+			Field{"url", req.URL},
+			Field{"header", redactor.RedactHeader(req.Header)},
+			Field{"body", string(redactor.RedactXML(call.Envelope))},
+		)...)
+	}
+
+	e := ErrorResponse{}
+
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return CanceledError{Err: ctxErr}
+		}
+		e.Err = err
+		return e
+	}
+	defer resp.Body.Close()
 
-		e.OpenSRSResponse = &oResp
+	e.HttpResponse = resp
+	call.HttpResponse = resp
+
+	logger.Log(LevelDebug, "opensrs response", append(fields,
+		Field{"http_status", resp.StatusCode},
+	)...)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		e.Err = fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+		return e
+	}
 
-		if oResp.IsSuccess != true {
-			return e
+	b, err := readResponseBody(resp.Body)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return CanceledError{Err: ctxErr}
 		}
+		e.Err = err
+		return e
+	}
+	call.ResponseBody = b
 
+	if _, ok := logger.(nopLogger); !ok {
+		logger.Log(LevelDebug, "opensrs response body", append(fields,
+			Field{"body", string(redactor.RedactXML(b))},
+		)...)
+	}
+
+	parsed, err := parseReply(b)
+	if err != nil {
+		e.Err = err
+		return e
+	}
+
+	call.parsed = parsed
+	call.Response = &parsed.base
+	e.OpenSRSResponse = call.Response
+
+	if call.Response.IsSuccess != true {
+		e.Err = responseError(call.Response)
+		return e
 	}
 
 	return nil
@@ -152,31 +388,41 @@ func getMD5Hash(text string) string {
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
-//func Bool(b bool) *bool {
-//	return &b
-//}
+// NewBool returns a pointer to b, for optional Bool fields.
+func NewBool(b bool) *Bool {
+	v := Bool(b)
+	return &v
+}
 
 func String(s string) *string {
 	return &s
 }
 
+// Bool is a boolean encoded as "1" or "0".
 type Bool bool
 
-func (b *Bool) UnmarshalJSON(data []byte) error {
-	value, err := parseBool(string(data))
+func (b Bool) MarshalOPS() (string, error) {
+	if b {
+		return "1", nil
+	}
+	return "0", nil
+}
+
+func (b *Bool) UnmarshalOPS(value string) error {
+	v, err := parseBool(value)
 	if err != nil {
 		return err
 	}
 
-	*b = Bool(value)
+	*b = Bool(v)
 	return nil
 }
 
 func parseBool(value string) (b bool, err error) {
 	switch value {
-	case `"1"`:
+	case "1":
 		b = true
-	case `"0"`:
+	case "0":
 		b = false
 	default:
 		err = fmt.Errorf("invalid value for bool: %s", value)
@@ -185,15 +431,14 @@ func parseBool(value string) (b bool, err error) {
 	return
 }
 
+// URL is a URL as OpenSRS returns it, which may be wrapped over several
+// lines and HTML escaped.
 type URL string
 
-func (u *URL) UnmarshalJSON(data []byte) error {
-	value := string(data)
+func (u *URL) UnmarshalOPS(value string) error {
 	value = strings.Replace(value, " ", "", -1)
-	value = strings.Replace(value, "\\n", "", -1)
+	value = strings.Replace(value, "\n", "", -1)
 	value = html.UnescapeString(value)
-	value = strings.Trim(value, `"`)
-	//value := strings.TrimSpace(string(data))
 	*u = URL(value)
 	return nil
 }
@@ -25,9 +25,8 @@ func setup() {
 	mux = http.NewServeMux()
 	server = httptest.NewServer(mux)
 
-	client = NewClient(apiUser, apiKey)
+	client = NewClient(apiUser, apiKey, WithBaseURL(server.URL))
 	client.Debug = true
-	client.BaseURL = server.URL + "/"
 }
 
 func teardown() {
@@ -60,6 +59,18 @@ func readFile(t *testing.T, path string) string {
 	return string(dat)
 }
 
+// readBody is called from HTTP handlers, which do not run on the test
+// goroutine, so it reports errors with t.Errorf instead of t.Fatalf.
+func readBody(t *testing.T, r *http.Request) []byte {
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Errorf("error reading body: %v", err)
+		return nil
+	}
+	return body
+}
+
 //func TestBuildXMLRequest(t *testing.T) {
 //	setup()
 //	defer teardown()
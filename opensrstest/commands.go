@@ -0,0 +1,668 @@
+package opensrstest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+type command struct {
+	action, object string
+}
+
+type handler func(s *Server, attr opensrs.Map) reply
+
+var handlers = map[command]handler{
+	{"LOOKUP", "DOMAIN"}:                      (*Server).lookup,
+	{"NAME_SUGGEST", "DOMAIN"}:                (*Server).nameSuggest,
+	{"SW_REGISTER", "DOMAIN"}:                 (*Server).register,
+	{"MODIFY", "DOMAIN"}:                      (*Server).modify,
+	{"RENEW", "DOMAIN"}:                       (*Server).renew,
+	{"GET", "DOMAIN"}:                         (*Server).get,
+	{"ADVANCED_UPDATE_NAMESERVERS", "DOMAIN"}: (*Server).updateNameservers,
+	{"CREATE", "NAMESERVER"}:                  (*Server).createNameserver,
+	{"GET", "NAMESERVER"}:                     (*Server).getNameserver,
+	{"MODIFY", "NAMESERVER"}:                  (*Server).modifyNameserver,
+	{"DELETE", "NAMESERVER"}:                  (*Server).deleteNameserver,
+	{"CREATE_DNS_ZONE", "DOMAIN"}:             (*Server).createZone,
+	{"GET_DNS_ZONE", "DOMAIN"}:                (*Server).getZone,
+	{"SET_DNS_ZONE", "DOMAIN"}:                (*Server).setZone,
+	{"DELETE_DNS_ZONE", "DOMAIN"}:             (*Server).deleteZone,
+	{"GET_BALANCE", "BALANCE"}:                (*Server).getBalance,
+}
+
+// handle answers req from the portfolio. s.mu must be held.
+func (s *Server) handle(req request) reply {
+	h, ok := handlers[command{req.Action, req.Object}]
+	if !ok {
+		return failure("441", fmt.Sprintf("Unsupported command %s %s", req.Action, req.Object))
+	}
+	if req.Attributes == nil {
+		req.Attributes = opensrs.Map{}
+	}
+	if _, ok := req.Attributes["domain"]; !ok && req.Domain != "" {
+		req.Attributes["domain"] = req.Domain
+	}
+	return h(s, req.Attributes)
+}
+
+func (s *Server) lookup(attr opensrs.Map) reply {
+	name, _ := attr.GetString("domain")
+	if name == "" {
+		return failure("465", "Missing domain")
+	}
+	if _, ok := s.domains[strings.ToLower(name)]; ok {
+		return success("211", "Domain taken", opensrs.Map{"status": "taken"})
+	}
+	return success("210", "Domain available", opensrs.Map{"status": "available"})
+}
+
+var suggestionPrefixes = []string{"get", "my", "the"}
+
+func (s *Server) nameSuggest(attr opensrs.Map) reply {
+	search, _ := attr.GetString("searchstring")
+	label := searchLabel(search)
+	if label == "" {
+		return failure("465", "Missing searchstring")
+	}
+
+	tlds := stringList(attr, "tlds")
+	if len(tlds) == 0 {
+		tlds = []string{".com", ".net", ".org"}
+	}
+	services := stringList(attr, "services")
+	if len(services) == 0 {
+		services = []string{"lookup", "suggestion"}
+	}
+
+	attributes := opensrs.Map{}
+	for _, service := range services {
+		var items []interface{}
+		switch service {
+		case "lookup":
+			for _, tld := range tlds {
+				items = append(items, s.suggestItem(label+tld))
+			}
+		case "suggestion":
+			for _, prefix := range suggestionPrefixes {
+				for _, tld := range tlds {
+					if item := s.suggestItem(prefix + label + tld); item["status"] == "available" {
+						items = append(items, item)
+					}
+				}
+			}
+		}
+		attributes[service] = opensrs.Map{
+			"is_success":    "1",
+			"response_code": "200",
+			"response_text": "Command completed successfully",
+			"count":         strconv.Itoa(len(items)),
+			"items":         items,
+		}
+	}
+	return success("200", "Command completed successfully", attributes)
+}
+
+func (s *Server) suggestItem(name string) opensrs.Map {
+	name = strings.ToLower(name)
+	status := "available"
+	if _, ok := s.domains[name]; ok {
+		status = "taken"
+	}
+	return opensrs.Map{"domain": name, "status": status}
+}
+
+// searchLabel turns a search string into a domain label, e.g. "Search
+// String" into "searchstring" and "example.com" into "example".
+func searchLabel(search string) string {
+	if i := strings.Index(search, "."); i >= 0 {
+		search = search[:i]
+	}
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-') {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, search)
+}
+
+var contactTypes = []string{"owner", "admin", "billing", "tech"}
+
+func (s *Server) register(attr opensrs.Map) reply {
+	name, _ := attr.GetString("domain")
+	name = strings.ToLower(name)
+	if name == "" {
+		return failure("465", "Missing domain")
+	}
+	if regType, _ := attr.GetString("reg_type"); regType != "" && regType != "new" {
+		return failure("465", "Unsupported reg_type "+regType)
+	}
+	if _, ok := s.domains[name]; ok {
+		return failure("485", "Domain already taken")
+	}
+
+	period, ok := intAttr(attr, "period", 1)
+	if !ok || period < 1 || period > 10 {
+		return failure("465", "Invalid period")
+	}
+
+	contacts := make(map[string]opensrs.Map)
+	for _, t := range contactTypes {
+		if c, ok := attr.GetMap("contact_set/" + t); ok {
+			contacts[t] = c
+		}
+	}
+	if contacts["owner"] == nil {
+		return failure("465", "Missing owner contact")
+	}
+	for _, t := range contactTypes {
+		if contacts[t] == nil {
+			contacts[t] = contacts["owner"]
+		}
+	}
+
+	var nameservers []string
+	if custom, _ := attr.GetString("custom_nameservers"); custom == "1" {
+		list, _ := attr.GetSlice("nameserver_list")
+		for _, ns := range list {
+			if m, ok := ns.(opensrs.Map); ok {
+				if host, _ := m.GetString("name"); host != "" {
+					nameservers = append(nameservers, strings.ToLower(host))
+				}
+			}
+		}
+		if len(nameservers) < 2 {
+			return failure("465", "At least two nameservers are required")
+		}
+	}
+
+	cost := s.Price * float64(period)
+	if cost > s.balance {
+		return InsufficientFunds().reply()
+	}
+	s.balance -= cost
+
+	autoRenew, _ := attr.GetString("auto_renew")
+	privacy, _ := attr.GetString("f_whois_privacy")
+	authInfo, _ := attr.GetString("auth_info")
+	s.domains[name] = &Domain{
+		Name:         name,
+		Expiry:       s.Now().AddDate(period, 0, 0),
+		AutoRenew:    autoRenew == "1",
+		WhoisPrivacy: privacy == "1",
+		AuthInfo:     authInfo,
+		Nameservers:  nameservers,
+		Contacts:     contacts,
+	}
+
+	s.nextID++
+	return success("200", "Domain registration successfully completed", opensrs.Map{
+		"id":                strconv.Itoa(s.nextID),
+		"registration_code": "200",
+		"registration_text": "Domain registration successfully completed",
+	})
+}
+
+// modify applies a MODIFY to the domain. With affect_domains set the
+// change is applied to the whole portfolio, which the fake treats as one
+// profile, and the outcome for each domain is returned in details.
+func (s *Server) modify(attr opensrs.Map) reply {
+	d, rep, ok := s.domainFor(attr)
+	if !ok {
+		return rep
+	}
+	if v, _ := attr.GetString("affect_domains"); v != "1" {
+		return modifyDomain(d, attr)
+	}
+
+	details := opensrs.Map{}
+	for name, d := range s.domains {
+		if d.Contacts == nil {
+			d.Contacts = make(map[string]opensrs.Map)
+		}
+		rep := modifyDomain(d, attr)
+		details[name] = opensrs.Map{
+			"is_success":    boolString(bool(rep.IsSuccess)),
+			"response_code": rep.ResponseCode,
+			"response_text": rep.ResponseText,
+		}
+	}
+	return success("200", "Command successful", opensrs.Map{"details": details})
+}
+
+func modifyDomain(d *Domain, attr opensrs.Map) reply {
+	data, _ := attr.GetString("data")
+	switch data {
+	case "contact_info":
+		for _, t := range contactTypes {
+			if c, ok := attr.GetMap("contact_set/" + t); ok {
+				d.Contacts[t] = c
+			}
+		}
+	case "expire_action":
+		if v, ok := attr.GetString("auto_renew"); ok {
+			d.AutoRenew = v == "1"
+		}
+	case "domain_auth_info":
+		v, _ := attr.GetString("domain_auth_info")
+		if v == "" {
+			return failure("465", "Missing domain_auth_info")
+		}
+		d.AuthInfo = v
+	case "status":
+		v, _ := attr.GetString("lock_state")
+		d.Locked = v == "1"
+	case "whois_privacy_state":
+		switch v, _ := attr.GetString("state"); v {
+		case "enable":
+			d.WhoisPrivacy = true
+		case "disable":
+			d.WhoisPrivacy = false
+		default:
+			return failure("465", "Invalid state "+v)
+		}
+	case "forwarding_email":
+		v, _ := attr.GetString("forwarding_email")
+		d.ForwardingEmail = v
+	case "change_ips_tag":
+		v, _ := attr.GetString("gaining_registrar")
+		if v == "" {
+			return failure("465", "Missing gaining_registrar")
+		}
+		d.IPSTag = v
+	case "nameserver_list":
+		list, _ := attr.GetSlice("nameserver_list")
+		for i := range list {
+			prefix := "nameserver_list/" + strconv.Itoa(i) + "/"
+			name, _ := attr.GetString(prefix + "name")
+			name = strings.ToLower(name)
+			switch action, _ := attr.GetString(prefix + "action"); action {
+			case "add":
+				if !contains(d.Nameservers, name) {
+					d.Nameservers = append(d.Nameservers, name)
+				}
+			case "remove":
+				d.Nameservers = remove(d.Nameservers, name)
+			case "update":
+				order, ok := intAttr(attr, prefix+"sortorder", 0)
+				if !ok || order < 1 || order > len(d.Nameservers) || !contains(d.Nameservers, name) {
+					return failure("465", "Invalid sortorder for "+name)
+				}
+				ns := remove(d.Nameservers, name)
+				ns = append(ns[:order-1], append([]string{name}, ns[order-1:]...)...)
+				d.Nameservers = ns
+			default:
+				return failure("465", "Invalid nameserver action "+action)
+			}
+		}
+	default:
+		return failure("465", "Unsupported data type "+data)
+	}
+	return success("200", "Command successful", nil)
+}
+
+func (s *Server) renew(attr opensrs.Map) reply {
+	d, rep, ok := s.domainFor(attr)
+	if !ok {
+		return rep
+	}
+
+	period, ok := intAttr(attr, "period", 1)
+	if !ok || period < 1 || period > 10 {
+		return failure("465", "Invalid period")
+	}
+	year, ok := intAttr(attr, "currentexpirationyear", 0)
+	if !ok || year == 0 {
+		return failure("465", "Missing currentexpirationyear")
+	}
+	if year != d.Expiry.Year() {
+		return failure("480", fmt.Sprintf("Current expiration year does not match, expected %d", d.Expiry.Year()))
+	}
+
+	cost := s.Price * float64(period)
+	if cost > s.balance {
+		return InsufficientFunds().reply()
+	}
+	s.balance -= cost
+	d.Expiry = d.Expiry.AddDate(period, 0, 0)
+
+	s.nextID++
+	return success("200", "Command completed successfully", opensrs.Map{
+		"auto_renew":                   boolString(d.AutoRenew),
+		"order_id":                     strconv.Itoa(s.nextID),
+		"registration expiration date": d.Expiry.UTC().Format(opensrs.TimeLayout),
+	})
+}
+
+func (s *Server) get(attr opensrs.Map) reply {
+	d, rep, ok := s.domainFor(attr)
+	if !ok {
+		return rep
+	}
+
+	t, _ := attr.GetString("type")
+	a := opensrs.Map{}
+	switch t {
+	case "all_info":
+		a["auto_renew"] = boolString(d.AutoRenew)
+		a["let_expire"] = "0"
+		a["expiredate"] = d.Expiry.UTC().Format(opensrs.TimeLayout)
+		a["registry_expiredate"] = d.Expiry.UTC().Format(opensrs.TimeLayout)
+		a["sponsoring_rsp"] = "1"
+		a["contact_set"] = contactSet(d, contactTypes...)
+		a["nameserver_list"] = s.nameserverList(d)
+	case "owner", "admin", "billing", "tech":
+		a["contact_set"] = contactSet(d, t)
+	case "nameservers":
+		a["nameserver_list"] = s.nameserverList(d)
+	case "status":
+		a["lock_state"] = boolString(d.Locked)
+		a["can_modify"] = "1"
+		a["transfer_away_in_progress"] = "0"
+	case "expire_action":
+		a["auto_renew"] = boolString(d.AutoRenew)
+		a["let_expire"] = "0"
+	case "whois_privacy_state":
+		a["state"] = "disabled"
+		if d.WhoisPrivacy {
+			a["state"] = "enabled"
+		}
+		a["changeable"] = "1"
+	case "domain_auth_info":
+		a["domain_auth_info"] = d.AuthInfo
+	case "forwarding_email":
+		a["forwarding_email"] = d.ForwardingEmail
+	case "list":
+		var names []string
+		for name := range s.domains {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		list := make([]interface{}, len(names))
+		for i, name := range names {
+			list[i] = opensrs.Map{"domain": name, "expiredate": s.domains[name].Expiry.UTC().Format(opensrs.TimeLayout)}
+		}
+		a["page"] = "1"
+		a["total"] = strconv.Itoa(len(list))
+		a["remainder"] = "0"
+		a["domain_list"] = list
+	default:
+		return failure("465", "Invalid type "+t)
+	}
+	return success("200", "Query Successful", a)
+}
+
+func contactSet(d *Domain, types ...string) opensrs.Map {
+	set := opensrs.Map{}
+	for _, t := range types {
+		if c, ok := d.Contacts[t]; ok {
+			set[t] = c
+		}
+	}
+	return set
+}
+
+func (s *Server) nameserverList(d *Domain) []interface{} {
+	list := make([]interface{}, len(d.Nameservers))
+	for i, ns := range d.Nameservers {
+		m := opensrs.Map{"name": ns, "sortorder": strconv.Itoa(i + 1)}
+		if ip, ok := s.hosts[ns]; ok {
+			m["ipaddress"] = ip
+		}
+		list[i] = m
+	}
+	return list
+}
+
+func (s *Server) updateNameservers(attr opensrs.Map) reply {
+	d, rep, ok := s.domainFor(attr)
+	if !ok {
+		return rep
+	}
+
+	opType, _ := attr.GetString("op_type")
+	var nameservers []string
+	switch opType {
+	case "assign":
+		nameservers = lowerAll(stringList(attr, "assign_ns"))
+	case "add_remove":
+		remove := make(map[string]bool)
+		for _, ns := range lowerAll(stringList(attr, "remove_ns")) {
+			remove[ns] = true
+		}
+		for _, ns := range d.Nameservers {
+			if !remove[ns] {
+				nameservers = append(nameservers, ns)
+			}
+		}
+		for _, ns := range lowerAll(stringList(attr, "add_ns")) {
+			if !contains(nameservers, ns) {
+				nameservers = append(nameservers, ns)
+			}
+		}
+	default:
+		return failure("465", "Invalid op_type "+opType)
+	}
+	if len(nameservers) < 2 {
+		return failure("465", "At least two nameservers are required")
+	}
+
+	d.Nameservers = nameservers
+	return success("200", "Command successful", nil)
+}
+
+func (s *Server) createNameserver(attr opensrs.Map) reply {
+	name, _ := attr.GetString("name")
+	ip, _ := attr.GetString("ipaddress")
+	name = strings.ToLower(name)
+	if name == "" || ip == "" {
+		return failure("465", "Missing name or ipaddress")
+	}
+	if _, ok := s.hosts[name]; ok {
+		return failure("480", "Nameserver already exists")
+	}
+	s.hosts[name] = ip
+	return success("200", "Command successful", opensrs.Map{"name": name, "ipaddress": ip})
+}
+
+func (s *Server) getNameserver(attr opensrs.Map) reply {
+	name, _ := attr.GetString("name")
+	name = strings.ToLower(name)
+
+	var names []string
+	if name == "all" {
+		for host := range s.hosts {
+			names = append(names, host)
+		}
+		sort.Strings(names)
+	} else if _, ok := s.hosts[name]; ok {
+		names = []string{name}
+	} else {
+		return failure("465", "Nameserver not found")
+	}
+
+	list := make([]interface{}, len(names))
+	for i, host := range names {
+		list[i] = opensrs.Map{"name": host, "ipaddress": s.hosts[host]}
+	}
+	return success("200", "Command successful", opensrs.Map{"nameserver_list": list})
+}
+
+func (s *Server) modifyNameserver(attr opensrs.Map) reply {
+	name, _ := attr.GetString("name")
+	name = strings.ToLower(name)
+	ip, ok := s.hosts[name]
+	if !ok {
+		return failure("465", "Nameserver not found")
+	}
+	if v, _ := attr.GetString("ipaddress"); v != "" {
+		ip = v
+	}
+	if newName, _ := attr.GetString("new_name"); newName != "" && strings.ToLower(newName) != name {
+		newName = strings.ToLower(newName)
+		if _, ok := s.hosts[newName]; ok {
+			return failure("480", "Nameserver already exists")
+		}
+		delete(s.hosts, name)
+		for _, d := range s.domains {
+			for i, ns := range d.Nameservers {
+				if ns == name {
+					d.Nameservers[i] = newName
+				}
+			}
+		}
+		name = newName
+	}
+	s.hosts[name] = ip
+	return success("200", "Command successful", nil)
+}
+
+func (s *Server) deleteNameserver(attr opensrs.Map) reply {
+	name, _ := attr.GetString("name")
+	name = strings.ToLower(name)
+	if _, ok := s.hosts[name]; !ok {
+		return failure("465", "Nameserver not found")
+	}
+	for _, d := range s.domains {
+		if contains(d.Nameservers, name) {
+			return failure("480", "Nameserver is in use by "+d.Name)
+		}
+	}
+	delete(s.hosts, name)
+	return success("200", "Command successful", nil)
+}
+
+func (s *Server) createZone(attr opensrs.Map) reply {
+	d, rep, ok := s.domainFor(attr)
+	if !ok {
+		return rep
+	}
+	if d.Zone != nil {
+		return failure("480", "DNS zone already exists")
+	}
+	d.Zone, _ = attr.GetMap("records")
+	if d.Zone == nil {
+		d.Zone = opensrs.Map{}
+	}
+	return success("200", "Command successful", zoneAttributes(d))
+}
+
+func (s *Server) getZone(attr opensrs.Map) reply {
+	d, rep, ok := s.zoneFor(attr)
+	if !ok {
+		return rep
+	}
+	return success("200", "Command successful", zoneAttributes(d))
+}
+
+func (s *Server) setZone(attr opensrs.Map) reply {
+	d, rep, ok := s.zoneFor(attr)
+	if !ok {
+		return rep
+	}
+	d.Zone, _ = attr.GetMap("records")
+	if d.Zone == nil {
+		d.Zone = opensrs.Map{}
+	}
+	return success("200", "Command successful", zoneAttributes(d))
+}
+
+func (s *Server) deleteZone(attr opensrs.Map) reply {
+	d, rep, ok := s.zoneFor(attr)
+	if !ok {
+		return rep
+	}
+	d.Zone = nil
+	return success("200", "Command successful", nil)
+}
+
+func (s *Server) getBalance(attr opensrs.Map) reply {
+	return success("200", "Command successful", opensrs.Map{
+		"balance":      strconv.FormatFloat(s.balance, 'f', 2, 64),
+		"hold_balance": "0.00",
+	})
+}
+
+// domainFor returns the portfolio domain named by the domain attribute, or
+// the reply to send when there is none.
+func (s *Server) domainFor(attr opensrs.Map) (*Domain, reply, bool) {
+	name, _ := attr.GetString("domain")
+	d, ok := s.domains[strings.ToLower(name)]
+	if !ok {
+		return nil, failure("415", "Authentication failed, domain not found in portfolio"), false
+	}
+	if d.Contacts == nil {
+		d.Contacts = make(map[string]opensrs.Map)
+	}
+	return d, reply{}, true
+}
+
+// zoneFor is domainFor for domains that must have a DNS zone.
+func (s *Server) zoneFor(attr opensrs.Map) (*Domain, reply, bool) {
+	d, rep, ok := s.domainFor(attr)
+	if ok && d.Zone == nil {
+		return nil, failure("465", "DNS zone does not exist"), false
+	}
+	return d, rep, ok
+}
+
+func zoneAttributes(d *Domain) opensrs.Map {
+	return opensrs.Map{"nameservers_ok": "1", "records": d.Zone}
+}
+
+func intAttr(attr opensrs.Map, key string, def int) (int, bool) {
+	v, ok := attr.GetString(key)
+	if !ok || v == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil
+}
+
+func stringList(attr opensrs.Map, key string) []string {
+	list, _ := attr.GetSlice(key)
+	var s []string
+	for _, v := range list {
+		if v, ok := v.(string); ok && v != "" {
+			s = append(s, v)
+		}
+	}
+	return s
+}
+
+func lowerAll(s []string) []string {
+	for i := range s {
+		s[i] = strings.ToLower(s[i])
+	}
+	return s
+}
+
+func contains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(s []string, v string) []string {
+	out := s[:0]
+	for _, x := range s {
+		if x != v {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func boolString(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,83 @@
+package opensrstest
+
+import (
+	"strings"
+	"time"
+)
+
+// Fault makes the fake misbehave for matching calls.
+type Fault struct {
+	// Action and Object select the calls to fail, empty matches any.
+	Action string
+	Object string
+	// Times is the number of calls to fail, 0 fails all of them until
+	// ClearFaults.
+	Times int
+
+	// Delay stalls the call before answering it, or until the client gives
+	// up.
+	Delay time.Duration
+	// HTTPStatus answers with a bare HTTP error instead of an OPS reply.
+	HTTPStatus int
+	// ResponseCode and ResponseText answer with a failed OPS reply.
+	ResponseCode string
+	ResponseText string
+}
+
+// AuthFailure rejects calls as if the credentials were wrong.
+func AuthFailure() Fault {
+	return Fault{ResponseCode: "400", ResponseText: "Authentication Error."}
+}
+
+// InsufficientFunds rejects calls as if the reseller balance was too low.
+func InsufficientFunds() Fault {
+	return Fault{ResponseCode: "486", ResponseText: "Insufficient funds in reseller account"}
+}
+
+// RegistryTimeout fails calls with a registry timeout, which the client
+// retries for read-only actions.
+func RegistryTimeout() Fault {
+	return Fault{ResponseCode: "705", ResponseText: "Registry timeout, try again later"}
+}
+
+// Timeout stalls calls for d, long enough for the client to time out.
+func Timeout(d time.Duration) Fault {
+	return Fault{Delay: d}
+}
+
+func (f Fault) reply() reply {
+	return failure(f.ResponseCode, f.ResponseText)
+}
+
+// Inject makes the fake apply f to matching calls. Faults are tried in the
+// order they were injected.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// fault returns the first fault that applies to a call and uses it up.
+func (s *Server) fault(action, object string) *Fault {
+	for i, f := range s.faults {
+		if f.Action != "" && !strings.EqualFold(f.Action, action) ||
+			f.Object != "" && !strings.EqualFold(f.Object, object) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package opensrstest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+// Reply builds an OPS response envelope. It starts out as a successful
+// reply, and implements http.Handler so it can answer a call directly.
+type Reply struct {
+	m opensrs.Map
+}
+
+// NewReply returns a successful reply for object.
+func NewReply(object string) *Reply {
+	return &Reply{m: opensrs.Map{
+		"protocol":      "XCP",
+		"action":        "REPLY",
+		"object":        object,
+		"is_success":    "1",
+		"response_code": "200",
+		"response_text": "Command successful",
+	}}
+}
+
+// Status sets the response code and text.
+func (r *Reply) Status(code, text string) *Reply {
+	r.m["response_code"] = code
+	r.m["response_text"] = text
+	return r
+}
+
+// Fail turns the reply into a failed one with the given code and text.
+func (r *Reply) Fail(code, text string) *Reply {
+	r.m["is_success"] = "0"
+	return r.Status(code, text)
+}
+
+// Set sets a top-level item next to the base response fields.
+func (r *Reply) Set(key string, value interface{}) *Reply {
+	r.m[key] = value
+	return r
+}
+
+// Attr sets an item of the attributes block.
+func (r *Reply) Attr(key string, value interface{}) *Reply {
+	attr, ok := r.m["attributes"].(opensrs.Map)
+	if !ok {
+		attr = opensrs.Map{}
+		r.m["attributes"] = attr
+	}
+	attr[key] = value
+	return r
+}
+
+// Bytes returns the envelope. It panics if a value cannot be encoded by
+// opensrs.ToXml.
+func (r *Reply) Bytes() []byte {
+	b, err := opensrs.ToXml(r.m)
+	if err != nil {
+		panic(fmt.Sprintf("opensrstest: cannot encode reply: %v", err))
+	}
+	return append([]byte(xmlHeader), b...)
+}
+
+func (r *Reply) String() string {
+	return string(r.Bytes())
+}
+
+func (r *Reply) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.Write(r.Bytes())
+}
+
+// LookupReply returns the reply to a LOOKUP of a domain with status
+// "available" or "taken".
+func LookupReply(status string) *Reply {
+	r := NewReply("DOMAIN").Attr("status", status)
+	switch status {
+	case "available":
+		r.Status("210", "Domain available")
+	case "taken":
+		r.Status("211", "Domain taken")
+	}
+	return r
+}
+
+// RegisterReply returns the reply to a successful SW_REGISTER with the
+// given order ID.
+func RegisterReply(orderID string) *Reply {
+	return NewReply("DOMAIN").
+		Status("200", "Domain registration successfully completed").
+		Attr("id", orderID).
+		Attr("registration_code", "200").
+		Attr("registration_text", "Domain registration successfully completed")
+}
+
+// NameSuggestBuilder builds the reply to a NAME_SUGGEST call.
+type NameSuggestBuilder struct {
+	label      string
+	tld        string
+	lookup     []interface{}
+	suggestion []interface{}
+	premium    []interface{}
+}
+
+// NewNameSuggest starts a NAME_SUGGEST reply for a search label such as
+// "example". Generated names use the .com TLD.
+func NewNameSuggest(label string) *NameSuggestBuilder {
+	return &NameSuggestBuilder{label: label, tld: ".com"}
+}
+
+// TLD sets the TLD of generated names.
+func (b *NameSuggestBuilder) TLD(tld string) *NameSuggestBuilder {
+	b.tld = tld
+	return b
+}
+
+// Lookup adds a lookup result.
+func (b *NameSuggestBuilder) Lookup(domain, status string) *NameSuggestBuilder {
+	b.lookup = append(b.lookup, opensrs.Map{"domain": domain, "status": status})
+	return b
+}
+
+// Suggestions adds n available suggestions.
+func (b *NameSuggestBuilder) Suggestions(n int) *NameSuggestBuilder {
+	for i := 0; i < n; i++ {
+		b.suggestion = append(b.suggestion, opensrs.Map{
+			"domain": b.generated("suggestion", len(b.suggestion)),
+			"status": "available",
+		})
+	}
+	return b
+}
+
+// Premium adds n available premium names at price.
+func (b *NameSuggestBuilder) Premium(n int, price string) *NameSuggestBuilder {
+	for i := 0; i < n; i++ {
+		b.premium = append(b.premium, opensrs.Map{
+			"domain": b.generated("premium", len(b.premium)),
+			"status": "available",
+			"price":  price,
+		})
+	}
+	return b
+}
+
+func (b *NameSuggestBuilder) generated(kind string, i int) string {
+	return fmt.Sprintf("%s%s%d%s", b.label, kind, i+1, b.tld)
+}
+
+// Reply returns the reply. Only services with results are included.
+func (b *NameSuggestBuilder) Reply() *Reply {
+	r := NewReply("DOMAIN").
+		Status("200", "Command completed successfully").
+		Set("is_search_completed", "1")
+	for service, items := range map[string][]interface{}{
+		"lookup":     b.lookup,
+		"suggestion": b.suggestion,
+		"premium":    b.premium,
+	} {
+		if len(items) == 0 {
+			continue
+		}
+		r.Attr(service, opensrs.Map{
+			"is_success":    "1",
+			"response_code": "200",
+			"response_text": "Command completed successfully",
+			"count":         strconv.Itoa(len(items)),
+			"items":         items,
+		})
+	}
+	return r
+}
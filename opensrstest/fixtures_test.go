@@ -0,0 +1,135 @@
+package opensrstest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+func TestMuxLookupReply(t *testing.T) {
+	m := NewMux(opensrs.WithStrictMode(opensrs.StrictError))
+	defer m.Close()
+
+	m.Handle("LOOKUP", "DOMAIN", LookupReply("taken"))
+
+	resp, err := m.Client.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attributes.Status != "taken" || resp.ResponseCode != "211" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	reqs := m.Requests()
+	if len(reqs) != 1 || reqs[0].Action != "LOOKUP" {
+		t.Fatalf("unexpected requests %+v", reqs)
+	}
+	if got, _ := reqs[0].Attributes.GetString("domain"); got != "example.com" {
+		t.Errorf("got domain %q", got)
+	}
+}
+
+func TestMuxNameSuggestReply(t *testing.T) {
+	m := NewMux(opensrs.WithStrictMode(opensrs.StrictError))
+	defer m.Close()
+
+	m.Handle("NAME_SUGGEST", "DOMAIN", NewNameSuggest("example").
+		Lookup("example.com", "taken").
+		Suggestions(3).
+		Premium(2, "2500.00").
+		Reply())
+
+	resp, err := m.Client.Domains.NameSuggest(opensrs.NameSuggestRequestAttributes{SearchString: "example"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a := resp.Attributes
+	if len(a.Lookup.Items) != 1 || a.Lookup.Items[0].Status != "taken" {
+		t.Errorf("unexpected lookup %+v", a.Lookup)
+	}
+	if a.Suggestion.Count != "3" || len(a.Suggestion.Items) != 3 {
+		t.Errorf("unexpected suggestions %+v", a.Suggestion)
+	}
+	if len(a.Premium.Items) != 2 || a.Premium.Items[1].Price != "2500.00" || a.Premium.Items[1].Domain != "examplepremium2.com" {
+		t.Errorf("unexpected premium %+v", a.Premium)
+	}
+	if !resp.IsSearchComplete {
+		t.Error("want the search to be complete")
+	}
+}
+
+func TestMuxFailures(t *testing.T) {
+	m := NewMux()
+	defer m.Close()
+
+	m.Handle("LOOKUP", "DOMAIN", NewReply("DOMAIN").Fail("465", "Invalid attribute value"))
+	if _, err := m.Client.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "x"}); !errors.Is(err, opensrs.ErrInvalidData) {
+		t.Errorf("want ErrInvalidData, got %v", err)
+	}
+
+	if _, err := m.Client.Domains.NameSuggest(opensrs.NameSuggestRequestAttributes{SearchString: "x"}); !errors.Is(err, opensrs.ErrCommandFailed) {
+		t.Errorf("unhandled call: want ErrCommandFailed, got %v", err)
+	}
+
+	c := opensrs.NewClient(MuxUsername, "wrong", opensrs.WithBaseURL(m.Server.URL), opensrs.WithRetryPolicy(nil))
+	if _, err := c.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "x"}); !errors.Is(err, opensrs.ErrAuthentication) {
+		t.Errorf("bad signature: want ErrAuthentication, got %v", err)
+	}
+}
+
+func TestMuxHandleFunc(t *testing.T) {
+	m := NewMux()
+	defer m.Close()
+
+	m.HandleFunc("LOOKUP", "DOMAIN", func(w http.ResponseWriter, r *http.Request) {
+		req, err := ReadRequest(r)
+		if err != nil {
+			t.Errorf("handler cannot read the request: %v", err)
+		}
+		status := "available"
+		if d, _ := req.Attributes.GetString("domain"); d == "taken.com" {
+			status = "taken"
+		}
+		LookupReply(status).ServeHTTP(w, r)
+	})
+
+	resp, err := m.Client.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "taken.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attributes.Status != "taken" {
+		t.Errorf("got status %q, want taken", resp.Attributes.Status)
+	}
+}
+
+func TestReplyBytes(t *testing.T) {
+	var resp opensrs.BaseResponse
+	b := NewReply("BALANCE").Attr("balance", "12.00").Bytes()
+	if err := opensrs.FromXml(b, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.IsSuccess != true || resp.Object != "BALANCE" || resp.ResponseCode != "200" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if got, _ := resp.Raw.GetString("attributes/balance"); got != "12.00" {
+		t.Errorf("got balance %q", got)
+	}
+}
+
+func TestMuxRegisterReply(t *testing.T) {
+	m := NewMux(opensrs.WithStrictMode(opensrs.StrictError))
+	defer m.Close()
+
+	m.Handle("SW_REGISTER", "DOMAIN", RegisterReply("42"))
+
+	resp, err := m.Client.Domains.Register(opensrs.RegisterRequestAttributes{Domain: "example.com", RegType: opensrs.RegTypeNew})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attributes.OrderID != "42" {
+		t.Errorf("got order ID %q, want 42", resp.Attributes.OrderID)
+	}
+}
@@ -0,0 +1,128 @@
+package opensrstest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+// ErrNotScripted is returned by mock methods that have no function set.
+var ErrNotScripted = errors.New("opensrstest: mock method not scripted")
+
+// MockCall is a call recorded by a mock. Method is the name of the method
+// without the Context suffix, Args holds the request attributes.
+type MockCall struct {
+	Method string
+	Ctx    context.Context
+	Args   interface{}
+}
+
+// MockGetArgs are the Args of a recorded Get call.
+type MockGetArgs struct {
+	Domain string
+	Type   opensrs.GetType
+}
+
+// MockDomains is a programmable opensrs.Domains. Each method calls the
+// matching function field, or returns ErrNotScripted when it is nil. The
+// plain methods pass context.Background() on to the functions.
+type MockDomains struct {
+	GetFunc         func(ctx context.Context, domain string, t opensrs.GetType) (*opensrs.GetResponse, error)
+	LookupFunc      func(ctx context.Context, attr opensrs.LookupRequestAttributes) (*opensrs.LookupResponse, error)
+	ModifyFunc      func(ctx context.Context, attr opensrs.ModifyRequestAttributes) (*opensrs.ModifyResponse, error)
+	NameSuggestFunc func(ctx context.Context, attr opensrs.NameSuggestRequestAttributes) (*opensrs.NameSuggestResponse, error)
+	RegisterFunc    func(ctx context.Context, attr opensrs.RegisterRequestAttributes) (*opensrs.RegisterResponse, error)
+	RenewFunc       func(ctx context.Context, attr opensrs.RenewRequestAttributes) (*opensrs.RenewResponse, error)
+
+	mu    sync.Mutex
+	calls []MockCall
+}
+
+var _ opensrs.Domains = (*MockDomains)(nil)
+
+// Calls returns the calls made so far.
+func (m *MockDomains) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+func (m *MockDomains) record(ctx context.Context, method string, args interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, MockCall{Method: method, Ctx: ctx, Args: args})
+}
+
+func (m *MockDomains) Get(domain string, t opensrs.GetType) (*opensrs.GetResponse, error) {
+	return m.GetContext(context.Background(), domain, t)
+}
+
+func (m *MockDomains) GetContext(ctx context.Context, domain string, t opensrs.GetType) (*opensrs.GetResponse, error) {
+	m.record(ctx, "Get", MockGetArgs{Domain: domain, Type: t})
+	if m.GetFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.GetFunc(ctx, domain, t)
+}
+
+func (m *MockDomains) Lookup(attr opensrs.LookupRequestAttributes) (*opensrs.LookupResponse, error) {
+	return m.LookupContext(context.Background(), attr)
+}
+
+func (m *MockDomains) LookupContext(ctx context.Context, attr opensrs.LookupRequestAttributes) (*opensrs.LookupResponse, error) {
+	m.record(ctx, "Lookup", attr)
+	if m.LookupFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.LookupFunc(ctx, attr)
+}
+
+func (m *MockDomains) Modify(attr opensrs.ModifyRequestAttributes) (*opensrs.ModifyResponse, error) {
+	return m.ModifyContext(context.Background(), attr)
+}
+
+func (m *MockDomains) ModifyContext(ctx context.Context, attr opensrs.ModifyRequestAttributes) (*opensrs.ModifyResponse, error) {
+	m.record(ctx, "Modify", attr)
+	if m.ModifyFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.ModifyFunc(ctx, attr)
+}
+
+func (m *MockDomains) NameSuggest(attr opensrs.NameSuggestRequestAttributes) (*opensrs.NameSuggestResponse, error) {
+	return m.NameSuggestContext(context.Background(), attr)
+}
+
+func (m *MockDomains) NameSuggestContext(ctx context.Context, attr opensrs.NameSuggestRequestAttributes) (*opensrs.NameSuggestResponse, error) {
+	m.record(ctx, "NameSuggest", attr)
+	if m.NameSuggestFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.NameSuggestFunc(ctx, attr)
+}
+
+func (m *MockDomains) Register(attr opensrs.RegisterRequestAttributes) (*opensrs.RegisterResponse, error) {
+	return m.RegisterContext(context.Background(), attr)
+}
+
+func (m *MockDomains) RegisterContext(ctx context.Context, attr opensrs.RegisterRequestAttributes) (*opensrs.RegisterResponse, error) {
+	m.record(ctx, "Register", attr)
+	if m.RegisterFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.RegisterFunc(ctx, attr)
+}
+
+func (m *MockDomains) Renew(attr opensrs.RenewRequestAttributes) (*opensrs.RenewResponse, error) {
+	return m.RenewContext(context.Background(), attr)
+}
+
+func (m *MockDomains) RenewContext(ctx context.Context, attr opensrs.RenewRequestAttributes) (*opensrs.RenewResponse, error) {
+	m.record(ctx, "Renew", attr)
+	if m.RenewFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.RenewFunc(ctx, attr)
+}
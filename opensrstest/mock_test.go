@@ -0,0 +1,61 @@
+package opensrstest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+// available is business logic written against the interface.
+func available(d opensrs.Domains, domain string) (bool, error) {
+	resp, err := d.Lookup(opensrs.LookupRequestAttributes{Domain: domain})
+	if err != nil {
+		return false, err
+	}
+	return resp.Attributes.Status == "available", nil
+}
+
+func TestMockDomains(t *testing.T) {
+	m := &MockDomains{
+		LookupFunc: func(ctx context.Context, attr opensrs.LookupRequestAttributes) (*opensrs.LookupResponse, error) {
+			resp := &opensrs.LookupResponse{}
+			resp.Attributes.Status = "taken"
+			if attr.Domain == "free.com" {
+				resp.Attributes.Status = "available"
+			}
+			return resp, nil
+		},
+	}
+
+	if ok, err := available(m, "free.com"); err != nil || !ok {
+		t.Errorf("free.com: got %v, %v", ok, err)
+	}
+	if ok, err := available(m, "example.com"); err != nil || ok {
+		t.Errorf("example.com: got %v, %v", ok, err)
+	}
+
+	calls := m.Calls()
+	if len(calls) != 2 || calls[0].Method != "Lookup" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+	if attr := calls[1].Args.(opensrs.LookupRequestAttributes); attr.Domain != "example.com" {
+		t.Errorf("unexpected args %+v", attr)
+	}
+
+	if _, err := m.NameSuggestContext(context.Background(), opensrs.NameSuggestRequestAttributes{}); !errors.Is(err, ErrNotScripted) {
+		t.Errorf("want ErrNotScripted, got %v", err)
+	}
+}
+
+func TestDomainsServiceAgainstMux(t *testing.T) {
+	m := NewMux()
+	defer m.Close()
+	m.Handle("LOOKUP", "DOMAIN", LookupReply("available"))
+
+	var d opensrs.Domains = m.Client.Domains
+	if ok, err := available(d, "example.com"); err != nil || !ok {
+		t.Errorf("got %v, %v", ok, err)
+	}
+}
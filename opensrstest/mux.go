@@ -0,0 +1,116 @@
+package opensrstest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+// Credentials of the client returned by NewMux.
+const (
+	MuxUsername = "reseller"
+	MuxAPIKey   = "api-key"
+)
+
+// Mux routes OPS calls to handlers by action and object, for unit tests
+// that only need canned replies. Calls are checked for valid credentials
+// first, and calls without a handler fail with response code 441.
+type Mux struct {
+	Server *httptest.Server
+	// Client is signed with MuxUsername and MuxAPIKey and does not retry.
+	Client *opensrs.Client
+
+	mu       sync.Mutex
+	handlers map[command]http.Handler
+	requests []Request
+}
+
+// NewMux starts a Mux with a client pointed at it, configured further by
+// opts. Close it when done.
+func NewMux(opts ...opensrs.Option) *Mux {
+	m := &Mux{handlers: make(map[command]http.Handler)}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serve))
+	m.Client = opensrs.NewClient(MuxUsername, MuxAPIKey, append([]opensrs.Option{
+		opensrs.WithBaseURL(m.Server.URL),
+		opensrs.WithRetryPolicy(nil),
+	}, opts...)...)
+	return m
+}
+
+// Handle routes calls of action on object to h. The request body can be
+// read again by h.
+func (m *Mux) Handle(action, object string, h http.Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[command{strings.ToUpper(action), strings.ToUpper(object)}] = h
+}
+
+// HandleFunc is Handle for a handler function.
+func (m *Mux) HandleFunc(action, object string, f func(http.ResponseWriter, *http.Request)) {
+	m.Handle(action, object, http.HandlerFunc(f))
+}
+
+// ReadRequest decodes the OPS request in the body of r, for handlers that
+// answer according to the attributes.
+func ReadRequest(r *http.Request) (Request, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return Request{}, err
+	}
+	var req request
+	if err := opensrs.FromXml(body, &req); err != nil {
+		return Request{}, err
+	}
+	req.Action = strings.ToUpper(req.Action)
+	req.Object = strings.ToUpper(req.Object)
+	return req.public(), nil
+}
+
+// Requests returns the calls received so far.
+func (m *Mux) Requests() []Request {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Request(nil), m.requests...)
+}
+
+// Close shuts the server down.
+func (m *Mux) Close() {
+	m.Server.Close()
+}
+
+func (m *Mux) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req request
+	if err := opensrs.FromXml(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Action = strings.ToUpper(req.Action)
+	req.Object = strings.ToUpper(req.Object)
+
+	m.mu.Lock()
+	m.requests = append(m.requests, req.public())
+	h, ok := m.handlers[command{req.Action, req.Object}]
+	m.mu.Unlock()
+
+	switch {
+	case r.Header.Get("X-Username") != MuxUsername || r.Header.Get("X-Signature") != signature(body, MuxAPIKey):
+		h = NewReply(req.Object).Fail("400", "Authentication Error.")
+	case !ok:
+		h = NewReply(req.Object).Fail("441", fmt.Sprintf("No handler for %s %s", req.Action, req.Object))
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	h.ServeHTTP(w, r)
+}
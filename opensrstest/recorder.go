@@ -0,0 +1,234 @@
+package opensrstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+// ErrNoInteraction is returned in replay mode for requests that the
+// cassette holds no unused interaction for.
+var ErrNoInteraction = errors.New("opensrstest: no recorded interaction")
+
+// RecorderMode selects whether a Recorder talks to the real API.
+type RecorderMode int
+
+const (
+	// ModeReplay answers every request from the cassette.
+	ModeReplay RecorderMode = iota
+	// ModeRecord sends every request to the API and records the exchange.
+	ModeRecord
+	// ModeAuto replays when the cassette file exists and records
+	// otherwise.
+	ModeAuto
+)
+
+// Cassette is the file format of a Recorder.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded OPS exchange. Requests are matched on Action,
+// Object, Domain and Attributes, the canonical encoding of the request
+// attributes with secrets masked. Request is kept for reading only.
+type Interaction struct {
+	Action string `json:"action"`
+	Object string `json:"object"`
+	// Domain is the top-level domain item of GET and MODIFY.
+	Domain     string `json:"domain,omitempty"`
+	Attributes string `json:"attributes"`
+	Request    string `json:"request"`
+	Status     int    `json:"status"`
+	Response   string `json:"response"`
+}
+
+// Recorder is an http.RoundTripper that records OPS exchanges to a
+// cassette file and replays them, so a session against the OpenSRS test
+// environment can be captured once and run in CI. Use it as the transport
+// of Client.HttpClient.
+//
+// Nothing but the envelopes is recorded, so the X-Username and
+// X-Signature headers never reach the cassette, and values masked by the
+// Redactor are scrubbed from both envelopes.
+type Recorder struct {
+	// Transport sends requests in record mode, nil means
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+	// Redactor scrubs secrets from the envelopes, nil means
+	// opensrs.DefaultRedactor.
+	Redactor *opensrs.Redactor
+
+	path      string
+	recording bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder returns a recorder for the cassette at path. In replay mode
+// the cassette must exist.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{path: path, recording: mode == ModeRecord}
+	if mode == ModeAuto {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			r.recording = true
+		}
+	}
+	if r.recording {
+		return r, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &r.cassette); err != nil {
+		return nil, fmt.Errorf("opensrstest: cassette %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Recording reports whether the recorder talks to the real API.
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// HTTPClient returns an http.Client that uses the recorder, for
+// opensrs.WithHTTPClient.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Save writes the recorded interactions to the cassette file. It does
+// nothing in replay mode.
+func (r *Recorder) Save() error {
+	if !r.recording {
+		return nil
+	}
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	in, err := r.interaction(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.recording {
+		return r.record(req, body, in)
+	}
+	return r.replay(req, in)
+}
+
+// interaction returns the matching key of a request body.
+func (r *Recorder) interaction(body []byte) (Interaction, error) {
+	var req request
+	if err := opensrs.FromXml(body, &req); err != nil {
+		return Interaction{}, fmt.Errorf("opensrstest: not an OPS request: %w", err)
+	}
+	attributes := []byte{}
+	if len(req.Attributes) > 0 {
+		b, err := opensrs.ToXml(req.Attributes)
+		if err != nil {
+			return Interaction{}, err
+		}
+		attributes = r.redactor().RedactXML(b)
+	}
+	return Interaction{
+		Action:     strings.ToUpper(req.Action),
+		Object:     strings.ToUpper(req.Object),
+		Domain:     strings.ToLower(req.Domain),
+		Attributes: string(attributes),
+		Request:    string(r.redactor().RedactXML(body)),
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, body []byte, in Interaction) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	in.Status = resp.StatusCode
+	in.Response = string(r.redactor().RedactXML(b))
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.mu.Unlock()
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return resp, nil
+}
+
+// replay answers from the first unused interaction that matches in, so
+// repeated requests are answered in the order they were recorded.
+func (r *Recorder) replay(req *http.Request, in Interaction) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, rec := range r.cassette.Interactions {
+		if r.used[i] || rec.Action != in.Action || rec.Object != in.Object || rec.Domain != in.Domain || rec.Attributes != in.Attributes {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+			StatusCode:    rec.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"text/xml"}},
+			Body:          ioutil.NopCloser(strings.NewReader(rec.Response)),
+			ContentLength: int64(len(rec.Response)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w for %s %s %s %s", ErrNoInteraction, in.Action, in.Object, in.Domain, in.Attributes)
+}
+
+func (r *Recorder) redactor() *opensrs.Redactor {
+	if r.Redactor != nil {
+		return r.Redactor
+	}
+	return opensrs.DefaultRedactor()
+}
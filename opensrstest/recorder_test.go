@@ -0,0 +1,156 @@
+package opensrstest
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opensrstest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassettes", "register.json")
+
+	register := opensrs.Map{
+		"domain":       "example.com",
+		"reg_username": "user",
+		"reg_password": "s3cret-password",
+		"contact_set":  opensrs.Map{"owner": owner},
+	}
+	session := func(c *opensrs.Client) []string {
+		var statuses []string
+		for i := 0; i < 2; i++ {
+			resp, err := c.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "example.com"})
+			if err != nil {
+				t.Fatalf("lookup: %v", err)
+			}
+			statuses = append(statuses, resp.Attributes.Status)
+			if i == 0 {
+				if _, _, err := c.Execute(context.Background(), "DOMAIN", "SW_REGISTER", register); err != nil {
+					t.Fatalf("register: %v", err)
+				}
+			}
+		}
+		return statuses
+	}
+
+	s := NewServer(apiUser, apiKey)
+	rec, err := NewRecorder(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Recording() {
+		t.Fatal("want ModeAuto to record without a cassette")
+	}
+	recorded := session(s.Client(opensrs.WithHTTPClient(rec.HTTPClient())))
+	s.Close()
+	if err := rec.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{apiUser, apiKey, "s3cret-password", "X-Signature"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+
+	// The replay neither needs the server nor the right credentials.
+	rec, err = NewRecorder(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Recording() {
+		t.Fatal("want ModeAuto to replay an existing cassette")
+	}
+	c := opensrs.NewClient("someone", "else", opensrs.WithBaseURL("http://opensrs.invalid"), opensrs.WithHTTPClient(rec.HTTPClient()))
+	register["reg_password"] = "another-password"
+	if got := session(c); strings.Join(got, ",") != strings.Join(recorded, ",") {
+		t.Errorf("replayed %v, recorded %v", got, recorded)
+	}
+	if strings.Join(recorded, ",") != "available,taken" {
+		t.Errorf("unexpected session %v", recorded)
+	}
+}
+
+func TestRecorderNoInteraction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opensrstest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "empty.json")
+	if err := ioutil.WriteFile(path, []byte(`{"interactions": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := opensrs.NewClient(apiUser, apiKey, opensrs.WithBaseURL("http://opensrs.invalid"), opensrs.WithHTTPClient(rec.HTTPClient()), opensrs.WithRetryPolicy(nil))
+	_, err = c.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "example.com"})
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("want ErrNoInteraction, got %v", err)
+	}
+
+	if _, err := NewRecorder(filepath.Join(dir, "missing.json"), ModeReplay); err == nil {
+		t.Error("want an error for a missing cassette in replay mode")
+	}
+}
+
+func TestRecorderMatchesDomain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opensrstest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "get.json")
+
+	s := NewServer(apiUser, apiKey)
+	s.AddDomain(Domain{Name: "a.com", Expiry: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)})
+	s.AddDomain(Domain{Name: "b.com", Expiry: time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)})
+	rec, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := s.Client(opensrs.WithHTTPClient(rec.HTTPClient()))
+	for _, name := range []string{"a.com", "b.com"} {
+		if _, err := c.Domains.Get(name, opensrs.GetAllInfo); err != nil {
+			t.Fatalf("get %s: %v", name, err)
+		}
+	}
+	s.Close()
+	if err := rec.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	rec, err = NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = opensrs.NewClient(apiUser, apiKey, opensrs.WithBaseURL("http://opensrs.invalid"), opensrs.WithHTTPClient(rec.HTTPClient()), opensrs.WithRetryPolicy(nil))
+	resp, err := c.Domains.Get("b.com", opensrs.GetAllInfo)
+	if err != nil {
+		t.Fatalf("replay get b.com: %v", err)
+	}
+	if got := resp.Attributes.ExpireDate; got == nil || got.Year() != 2031 {
+		t.Errorf("replayed the wrong domain, expires %v", got)
+	}
+	if _, err := c.Domains.Get("b.com", opensrs.GetAllInfo); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("want ErrNoInteraction for a second b.com, got %v", err)
+	}
+}
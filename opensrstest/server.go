@@ -0,0 +1,260 @@
+// Package opensrstest provides an in-process fake of the OpenSRS API for
+// end-to-end tests that run offline.
+//
+// The fake checks the X-Username and X-Signature headers like the real
+// API, keeps an in-memory portfolio of domains, nameservers and DNS zones,
+// and can be told to fail calls on purpose.
+package opensrstest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+const xmlHeader = "<?xml version='1.0' encoding='UTF-8' standalone='no' ?><!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'>"
+
+// Server is a fake OpenSRS API. Its zero value is not usable, create one
+// with NewServer.
+type Server struct {
+	*httptest.Server
+
+	Username string
+	APIKey   string
+	// Price is charged per domain and year on registration and renewal.
+	Price float64
+	// Now is the clock used for expiry dates.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	balance  float64
+	domains  map[string]*Domain
+	hosts    map[string]string
+	faults   []*Fault
+	requests []Request
+	nextID   int
+}
+
+// Domain is a domain in the fake portfolio.
+type Domain struct {
+	Name         string
+	Expiry       time.Time
+	AutoRenew    bool
+	Locked       bool
+	WhoisPrivacy bool
+	AuthInfo     string
+	// ForwardingEmail is the address mail for the domain is forwarded to.
+	ForwardingEmail string
+	// IPSTag is the .uk registrar tag, set by a change_ips_tag MODIFY.
+	IPSTag      string
+	Nameservers []string
+	// Contacts holds the contact sets by type: owner, admin, billing and
+	// tech.
+	Contacts map[string]opensrs.Map
+	// Zone holds the DNS records by type, nil when the domain has no zone.
+	Zone opensrs.Map
+}
+
+// Request is a call received by the fake.
+type Request struct {
+	Action string
+	Object string
+	// Domain is the top-level domain item that GET and MODIFY send in
+	// place of a cookie, empty for other calls.
+	Domain     string
+	Attributes opensrs.Map
+}
+
+// NewServer starts a fake that accepts the given credentials, with a
+// balance of 1000 and a price of 10 per domain and year. Close it when
+// done.
+func NewServer(username, apiKey string) *Server {
+	s := &Server{
+		Username: username,
+		APIKey:   apiKey,
+		Price:    10,
+		Now:      time.Now,
+		balance:  1000,
+		domains:  make(map[string]*Domain),
+		hosts:    make(map[string]string),
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Client returns a client for the fake, configured further by opts.
+func (s *Server) Client(opts ...opensrs.Option) *opensrs.Client {
+	return opensrs.NewClient(s.Username, s.APIKey, append([]opensrs.Option{opensrs.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// AddDomain puts d in the portfolio, replacing any domain of that name.
+func (s *Server) AddDomain(d Domain) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d.Name = strings.ToLower(d.Name)
+	s.domains[d.Name] = &d
+}
+
+// Domain returns a copy of the named domain in the portfolio. Its
+// Contacts and Zone are shared with the fake and must not be modified.
+func (s *Server) Domain(name string) (Domain, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.domains[strings.ToLower(name)]
+	if !ok {
+		return Domain{}, false
+	}
+	c := *d
+	c.Nameservers = append([]string(nil), d.Nameservers...)
+	return c, true
+}
+
+// AddNameserver registers a host with its IP address.
+func (s *Server) AddNameserver(name, ip string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hosts[strings.ToLower(name)] = ip
+}
+
+// Balance returns the reseller balance.
+func (s *Server) Balance() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.balance
+}
+
+// SetBalance sets the reseller balance.
+func (s *Server) SetBalance(b float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance = b
+}
+
+// Requests returns the calls received so far, including rejected ones.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// request is the part of an OPS request the fake looks at.
+type request struct {
+	Action string `opensrs:"action"`
+	Object string `opensrs:"object"`
+	// Domain names the domain in place of a cookie.
+	Domain     string      `opensrs:"domain"`
+	Attributes opensrs.Map `opensrs:"attributes"`
+}
+
+func (req request) public() Request {
+	return Request{Action: req.Action, Object: req.Object, Domain: req.Domain, Attributes: req.Attributes}
+}
+
+// reply is an OPS response.
+type reply struct {
+	Protocol     string       `opensrs:"protocol"`
+	Action       string       `opensrs:"action"`
+	Object       string       `opensrs:"object"`
+	IsSuccess    opensrs.Bool `opensrs:"is_success"`
+	ResponseCode string       `opensrs:"response_code"`
+	ResponseText string       `opensrs:"response_text"`
+	Attributes   opensrs.Map  `opensrs:"attributes,omitempty"`
+}
+
+func success(code, text string, attributes opensrs.Map) reply {
+	return reply{IsSuccess: true, ResponseCode: code, ResponseText: text, Attributes: attributes}
+}
+
+func failure(code, text string) reply {
+	return reply{ResponseCode: code, ResponseText: text}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req request
+	if err := opensrs.FromXml(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Action = strings.ToUpper(req.Action)
+	req.Object = strings.ToUpper(req.Object)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req.public())
+	s.mu.Unlock()
+
+	if !s.authorized(r.Header, body) {
+		s.write(w, req, AuthFailure().reply())
+		return
+	}
+
+	s.mu.Lock()
+	f := s.fault(req.Action, req.Object)
+	s.mu.Unlock()
+
+	if f != nil {
+		if f.Delay > 0 {
+			t := time.NewTimer(f.Delay)
+			select {
+			case <-t.C:
+			case <-r.Context().Done():
+				t.Stop()
+				return
+			}
+		}
+		if f.HTTPStatus != 0 {
+			http.Error(w, http.StatusText(f.HTTPStatus), f.HTTPStatus)
+			return
+		}
+		if f.ResponseCode != "" {
+			s.write(w, req, f.reply())
+			return
+		}
+	}
+
+	s.mu.Lock()
+	rep := s.handle(req)
+	s.mu.Unlock()
+	s.write(w, req, rep)
+}
+
+// authorized checks the credentials of a request the way OpenSRS does.
+func (s *Server) authorized(h http.Header, body []byte) bool {
+	return h.Get("X-Username") == s.Username && h.Get("X-Signature") == signature(body, s.APIKey)
+}
+
+func signature(body []byte, key string) string {
+	return md5Hex(md5Hex(string(body)+key) + key)
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Server) write(w http.ResponseWriter, req request, rep reply) {
+	rep.Protocol = "XCP"
+	rep.Action = "REPLY"
+	rep.Object = req.Object
+	b, err := opensrs.ToXml(rep)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprint(w, xmlHeader)
+	w.Write(b)
+}
@@ -0,0 +1,466 @@
+package opensrstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	opensrs "github.com/hamochi/go-opensrs"
+)
+
+const (
+	apiUser = "testUser"
+	apiKey  = "testApiKey"
+)
+
+var owner = opensrs.Map{
+	"first_name": "Jane",
+	"last_name":  "Doe",
+	"email":      "jane@example.com",
+	"country":    "CA",
+}
+
+func setup() (*Server, *opensrs.Client) {
+	s := NewServer(apiUser, apiKey)
+	return s, s.Client(opensrs.WithRetryPolicy(nil))
+}
+
+func execute(t *testing.T, c *opensrs.Client, object, action string, attr opensrs.Map) (opensrs.Map, error) {
+	t.Helper()
+	m, _, err := c.Execute(context.Background(), object, action, attr)
+	return m, err
+}
+
+func TestLookupRegisterLookup(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+
+	resp, err := c.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if resp.Attributes.Status != "available" {
+		t.Errorf("got status %q, want available", resp.Attributes.Status)
+	}
+
+	_, err = execute(t, c, "DOMAIN", "SW_REGISTER", opensrs.Map{
+		"domain":             "example.com",
+		"period":             "2",
+		"reg_type":           "new",
+		"contact_set":        opensrs.Map{"owner": owner},
+		"custom_nameservers": "1",
+		"nameserver_list": []opensrs.Map{
+			{"name": "ns1.example.net", "sortorder": "1"},
+			{"name": "ns2.example.net", "sortorder": "2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	resp, err = c.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if resp.Attributes.Status != "taken" {
+		t.Errorf("got status %q, want taken", resp.Attributes.Status)
+	}
+
+	d, ok := s.Domain("example.com")
+	if !ok {
+		t.Fatal("example.com is not in the portfolio")
+	}
+	if d.Contacts["tech"]["email"] != "jane@example.com" {
+		t.Errorf("want the owner copied to missing contacts, got %v", d.Contacts)
+	}
+	if len(d.Nameservers) != 2 || d.Nameservers[0] != "ns1.example.net" {
+		t.Errorf("unexpected nameservers %v", d.Nameservers)
+	}
+	if got := s.Balance(); got != 980 {
+		t.Errorf("got balance %v, want 980", got)
+	}
+
+	_, err = execute(t, c, "DOMAIN", "SW_REGISTER", opensrs.Map{
+		"domain":      "example.com",
+		"contact_set": opensrs.Map{"owner": owner},
+	})
+	if !errors.Is(err, opensrs.ErrDomainTaken) {
+		t.Errorf("registering twice: want ErrDomainTaken, got %v", err)
+	}
+}
+
+func TestNameSuggest(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+	s.AddDomain(Domain{Name: "searchstring.com"})
+
+	resp, err := c.Domains.NameSuggest(opensrs.NameSuggestRequestAttributes{
+		SearchString: "Search String",
+		Services:     []string{"lookup", "suggestion"},
+		TLDs:         []string{".COM", ".net"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lookup := resp.Attributes.Lookup
+	if lookup.Count != "2" || len(lookup.Items) != 2 {
+		t.Fatalf("unexpected lookup %+v", lookup)
+	}
+	if lookup.Items[0].Domain != "searchstring.com" || lookup.Items[0].Status != "taken" {
+		t.Errorf("unexpected first item %+v", lookup.Items[0])
+	}
+	if lookup.Items[1].Status != "available" {
+		t.Errorf("unexpected second item %+v", lookup.Items[1])
+	}
+	if len(resp.Attributes.Suggestion.Items) == 0 {
+		t.Error("no suggestions")
+	}
+}
+
+func TestModifyAndRenew(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+	expiry := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+	s.AddDomain(Domain{Name: "example.com", Expiry: expiry})
+
+	_, err := execute(t, c, "DOMAIN", "MODIFY", opensrs.Map{
+		"domain":     "example.com",
+		"data":       "expire_action",
+		"auto_renew": "1",
+	})
+	if err != nil {
+		t.Fatalf("modify: %v", err)
+	}
+	if d, _ := s.Domain("example.com"); !d.AutoRenew {
+		t.Error("auto_renew was not set")
+	}
+
+	_, err = execute(t, c, "DOMAIN", "RENEW", opensrs.Map{
+		"domain":                "example.com",
+		"period":                "1",
+		"currentexpirationyear": "2029",
+		"handle":                "process",
+	})
+	if err == nil {
+		t.Fatal("want an error for the wrong expiration year")
+	}
+
+	m, err := execute(t, c, "DOMAIN", "RENEW", opensrs.Map{
+		"domain":                "example.com",
+		"period":                "2",
+		"currentexpirationyear": "2030",
+		"handle":                "process",
+	})
+	if err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if got, _ := m.GetString("attributes/registration expiration date"); got != "2032-05-01 00:00:00" {
+		t.Errorf("got expiration %q", got)
+	}
+
+	_, err = execute(t, c, "DOMAIN", "RENEW", opensrs.Map{
+		"domain":                "unknown.com",
+		"currentexpirationyear": "2030",
+	})
+	if !errors.Is(err, opensrs.ErrAuthentication) {
+		t.Errorf("renewing a domain outside the portfolio: want ErrAuthentication, got %v", err)
+	}
+}
+
+func TestTypedRenew(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+	s.AddDomain(Domain{Name: "example.com", Expiry: time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)})
+
+	attr := opensrs.RenewRequestAttributes{Domain: "example.com", Period: 1, CurrentExpirationYear: 2030}
+	resp, err := c.Domains.Renew(attr)
+	if err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if want := time.Date(2031, 5, 1, 0, 0, 0, 0, time.UTC); !resp.Attributes.RegistrationExpirationDate.Equal(want) || resp.Attributes.OrderID == "" {
+		t.Errorf("unexpected attributes %+v", resp.Attributes)
+	}
+
+	// Sending the same order again must not renew the domain twice.
+	_, err = c.Domains.Renew(attr)
+	var e opensrs.ExpirationYearMismatchError
+	if !errors.As(err, &e) || e.Year != 2030 || e.Current != 2031 {
+		t.Errorf("want ExpirationYearMismatchError, got %v", err)
+	}
+	if d, _ := s.Domain("example.com"); d.Expiry.Year() != 2031 {
+		t.Errorf("domain renewed twice, expires %v", d.Expiry)
+	}
+}
+
+func TestTypedModify(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+	s.AddDomain(Domain{Name: "example.com", Nameservers: []string{"ns1.example.net", "ns2.example.net"}})
+	s.AddDomain(Domain{Name: "example.net"})
+
+	changes := []opensrs.ModifyData{
+		opensrs.ModifyWhoisPrivacy{State: opensrs.WhoisPrivacyEnable},
+		opensrs.ModifyForwardingEmail{ForwardingEmail: "owner@example.org"},
+		opensrs.ModifyIPSTag{GainingRegistrar: "TUCOWS-CA"},
+		opensrs.ModifyNameservers{NameserverList: []opensrs.NameserverChange{
+			{Action: opensrs.NameserverAdd, Name: "ns3.example.net"},
+			{Action: opensrs.NameserverRemove, Name: "ns1.example.net"},
+			{Action: opensrs.NameserverUpdate, Name: "ns3.example.net", SortOrder: 1},
+		}},
+	}
+	for _, change := range changes {
+		if _, err := c.Domains.Modify(opensrs.ModifyRequestAttributes{Domain: "example.com", Change: change}); err != nil {
+			t.Fatalf("modify %T: %v", change, err)
+		}
+	}
+
+	d, _ := s.Domain("example.com")
+	if !d.WhoisPrivacy || d.ForwardingEmail != "owner@example.org" || d.IPSTag != "TUCOWS-CA" {
+		t.Errorf("unexpected domain %+v", d)
+	}
+	if want := []string{"ns3.example.net", "ns2.example.net"}; !reflect.DeepEqual(d.Nameservers, want) {
+		t.Errorf("unexpected nameservers, want %v, got %v", want, d.Nameservers)
+	}
+
+	resp, err := c.Domains.Modify(opensrs.ModifyRequestAttributes{
+		Domain:        "example.com",
+		Change:        opensrs.ModifyStatus{LockState: true},
+		AffectDomains: true,
+	})
+	if err != nil {
+		t.Fatalf("modify affect_domains: %v", err)
+	}
+	if len(resp.Attributes.Details) != 2 || !resp.Attributes.Details["example.net"].IsSuccess {
+		t.Errorf("unexpected details %+v", resp.Attributes.Details)
+	}
+	if d, _ := s.Domain("example.net"); !d.Locked {
+		t.Error("linked domain was not locked")
+	}
+}
+
+func TestNameservers(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+	s.AddDomain(Domain{Name: "example.com", Nameservers: []string{"ns1.example.net", "ns2.example.net"}})
+
+	for _, name := range []string{"ns1.example.com", "ns2.example.com"} {
+		if _, err := execute(t, c, "NAMESERVER", "CREATE", opensrs.Map{"name": name, "ipaddress": "192.0.2.1"}); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+
+	_, err := execute(t, c, "DOMAIN", "ADVANCED_UPDATE_NAMESERVERS", opensrs.Map{
+		"domain":    "example.com",
+		"op_type":   "add_remove",
+		"add_ns":    []string{"ns1.example.com"},
+		"remove_ns": []string{"ns2.example.net"},
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	d, _ := s.Domain("example.com")
+	if len(d.Nameservers) != 2 || d.Nameservers[1] != "ns1.example.com" {
+		t.Errorf("unexpected nameservers %v", d.Nameservers)
+	}
+
+	if _, err := execute(t, c, "NAMESERVER", "DELETE", opensrs.Map{"name": "ns1.example.com"}); !errors.Is(err, opensrs.ErrNotAllowed) {
+		t.Errorf("deleting a nameserver in use: want ErrNotAllowed, got %v", err)
+	}
+
+	m, err := execute(t, c, "NAMESERVER", "GET", opensrs.Map{"name": "all"})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if list, _ := m.GetSlice("attributes/nameserver_list"); len(list) != 2 {
+		t.Errorf("want 2 nameservers, got %v", list)
+	}
+}
+
+func TestDNSZone(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+	s.AddDomain(Domain{Name: "example.com"})
+
+	attr := opensrs.Map{"domain": "example.com"}
+	if _, err := execute(t, c, "DOMAIN", "GET_DNS_ZONE", attr); err == nil {
+		t.Error("want an error before the zone exists")
+	}
+
+	_, err := execute(t, c, "DOMAIN", "CREATE_DNS_ZONE", opensrs.Map{
+		"domain":  "example.com",
+		"records": opensrs.Map{"A": []opensrs.Map{{"subdomain": "www", "ip_address": "192.0.2.1"}}},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	_, err = execute(t, c, "DOMAIN", "SET_DNS_ZONE", opensrs.Map{
+		"domain":  "example.com",
+		"records": opensrs.Map{"TXT": []opensrs.Map{{"subdomain": "", "text": "hello"}}},
+	})
+	if err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	m, err := execute(t, c, "DOMAIN", "GET_DNS_ZONE", attr)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got, _ := m.GetString("attributes/records/TXT/0/text"); got != "hello" {
+		t.Errorf("got TXT %q, want hello", got)
+	}
+	if _, ok := m.Get("attributes/records/A"); ok {
+		t.Error("SET_DNS_ZONE should replace the records")
+	}
+
+	if _, err := execute(t, c, "DOMAIN", "DELETE_DNS_ZONE", attr); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if d, _ := s.Domain("example.com"); d.Zone != nil {
+		t.Errorf("zone was not deleted: %v", d.Zone)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	s, _ := setup()
+	defer s.Close()
+
+	c := opensrs.NewClient(apiUser, "wrong", opensrs.WithBaseURL(s.URL), opensrs.WithRetryPolicy(nil))
+	_, err := c.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "example.com"})
+	if !errors.Is(err, opensrs.ErrAuthentication) {
+		t.Errorf("want ErrAuthentication, got %v", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+	lookup := opensrs.LookupRequestAttributes{Domain: "example.com"}
+
+	s.Inject(Fault{Action: "LOOKUP", Times: 1, ResponseCode: "400", ResponseText: "Authentication Error."})
+	if _, err := c.Domains.Lookup(lookup); !errors.Is(err, opensrs.ErrAuthentication) {
+		t.Errorf("want ErrAuthentication, got %v", err)
+	}
+	if _, err := c.Domains.Lookup(lookup); err != nil {
+		t.Errorf("the fault should be used up, got %v", err)
+	}
+
+	s.Inject(InsufficientFunds())
+	_, err := execute(t, c, "DOMAIN", "SW_REGISTER", opensrs.Map{
+		"domain":      "example.com",
+		"contact_set": opensrs.Map{"owner": owner},
+	})
+	if !errors.Is(err, opensrs.ErrInsufficientFunds) {
+		t.Errorf("want ErrInsufficientFunds, got %v", err)
+	}
+	s.ClearFaults()
+
+	s.Inject(Fault{HTTPStatus: http.StatusServiceUnavailable, Times: 1})
+	if _, err := c.Domains.Lookup(lookup); !errors.Is(err, opensrs.ErrUnexpectedStatus) {
+		t.Errorf("want ErrUnexpectedStatus, got %v", err)
+	}
+
+	s.Inject(Timeout(time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.Domains.LookupContext(ctx, lookup)
+	var canceled opensrs.CanceledError
+	if !errors.As(err, &canceled) {
+		t.Errorf("want CanceledError, got %v", err)
+	}
+}
+
+func TestRegistryTimeoutIsRetried(t *testing.T) {
+	s := NewServer(apiUser, apiKey)
+	defer s.Close()
+	c := s.Client(opensrs.WithRetryPolicy(&opensrs.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	f := RegistryTimeout()
+	f.Times = 2
+	s.Inject(f)
+
+	if _, err := c.Domains.Lookup(opensrs.LookupRequestAttributes{Domain: "example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(s.Requests()); got != 3 {
+		t.Errorf("got %d requests, want 3", got)
+	}
+}
+
+func TestInsufficientBalance(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+	s.SetBalance(5)
+
+	_, err := execute(t, c, "DOMAIN", "SW_REGISTER", opensrs.Map{
+		"domain":      "example.com",
+		"contact_set": opensrs.Map{"owner": owner},
+	})
+	if !errors.Is(err, opensrs.ErrInsufficientFunds) {
+		t.Errorf("want ErrInsufficientFunds, got %v", err)
+	}
+	if _, ok := s.Domain("example.com"); ok {
+		t.Error("the domain should not be registered")
+	}
+}
+
+func TestTypedRegister(t *testing.T) {
+	s, c := setup()
+	defer s.Close()
+
+	contact := &opensrs.Contact{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", Country: "CA"}
+	resp, err := c.Domains.Register(opensrs.RegisterRequestAttributes{
+		Domain:     "example.org",
+		Period:     3,
+		RegType:    opensrs.RegTypeNew,
+		ContactSet: opensrs.ContactSet{Owner: contact, Admin: contact},
+		NameserverList: []opensrs.Nameserver{
+			{Name: "ns1.example.net", SortOrder: 1},
+			{Name: "ns2.example.net", SortOrder: 2},
+		},
+		AutoRenew: opensrs.NewBool(true),
+		AuthInfo:  "EPP-CODE",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attributes.OrderID == "" {
+		t.Error("want an order ID")
+	}
+
+	d, ok := s.Domain("example.org")
+	if !ok {
+		t.Fatal("example.org is not in the portfolio")
+	}
+	if !d.AutoRenew || len(d.Nameservers) != 2 || d.Contacts["owner"]["first_name"] != "Jane" || d.AuthInfo != "EPP-CODE" {
+		t.Errorf("unexpected domain %+v", d)
+	}
+	if got := s.Balance(); got != 970 {
+		t.Errorf("got balance %v, want 970", got)
+	}
+
+	info, err := c.Domains.Get("example.org", opensrs.GetAllInfo)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if a := info.Attributes; a.ExpireDate == nil || !a.ExpireDate.Equal(d.Expiry.Truncate(time.Second)) || a.ContactSet.Admin.FirstName != "Jane" || len(a.NameserverList) != 2 {
+		t.Errorf("unexpected all_info %+v", a)
+	}
+
+	status, err := c.Domains.Get("example.org", opensrs.GetStatus)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if a := status.Attributes; a.LockState == nil || *a.LockState {
+		t.Errorf("the domain should not be locked, got %v", a.LockState)
+	}
+
+	reqs := s.Requests()
+	if last := reqs[len(reqs)-1]; last.Action != "GET" || last.Domain != "example.org" {
+		t.Errorf("unexpected request %+v", last)
+	}
+}
@@ -0,0 +1,122 @@
+package opensrs
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Environment selects the OpenSRS endpoint a Client talks to.
+type Environment string
+
+const (
+	// Live is the production environment.
+	Live Environment = "live"
+	// Test is the OpenSRS test environment (horizon).
+	Test Environment = "test"
+)
+
+var environmentURLs = map[Environment]string{
+	Live: defaultBaseURL,
+	Test: testBaseURL,
+}
+
+// ParseEnvironment returns the Environment named s ("live" or "test").
+func ParseEnvironment(s string) (Environment, error) {
+	env := Environment(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := environmentURLs[env]; !ok {
+		return "", fmt.Errorf("opensrs: unknown environment %q", s)
+	}
+	return env, nil
+}
+
+// Option configures a Client in NewClient.
+type Option func(c *Client)
+
+// WithEnvironment points the client at the live or test environment. It
+// panics on any other value rather than falling back to live; use
+// ParseEnvironment to check names read from configuration.
+func WithEnvironment(env Environment) Option {
+	parsed, err := ParseEnvironment(string(env))
+	if err != nil {
+		panic(err)
+	}
+	return func(c *Client) {
+		c.BaseURL = environmentURLs[parsed]
+	}
+}
+
+// WithBaseURL points the client at a custom endpoint.
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		c.BaseURL = url
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.HttpClient = hc
+	}
+}
+
+// WithTimeout limits the time a single HTTP attempt may take. It is
+// applied after all other options, so it also holds for an *http.Client
+// passed to WithHTTPClient later on. That client is not changed, but a
+// copy of it.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = &d
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.UserAgent = ua
+	}
+}
+
+// WithLogger sets the Logger that receives the client's log entries.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.Logger = l
+	}
+}
+
+// WithRedactor sets the Redactor applied before anything is logged.
+func WithRedactor(r *Redactor) Option {
+	return func(c *Client) {
+		c.Redactor = r
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy, nil disables retries.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(c *Client) {
+		c.RetryPolicy = p
+	}
+}
+
+// WithRateLimiter throttles the calls made by the client.
+func WithRateLimiter(rl *RateLimiter) Option {
+	return func(c *Client) {
+		c.RateLimiter = rl
+	}
+}
+
+// WithInterceptors appends interceptors to the client's chain.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Client) {
+		c.Interceptors = append(c.Interceptors, interceptors...)
+	}
+}
+
+// WithStrictMode sets how response items that the response type cannot
+// represent are reported.
+func WithStrictMode(m StrictMode) Option {
+	return func(c *Client) {
+		c.Strict = m
+	}
+}
@@ -0,0 +1,105 @@
+package opensrs
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewClientDefaults(t *testing.T) {
+	c := NewClient(apiUser, apiKey)
+	if c.BaseURL != defaultBaseURL {
+		t.Errorf("want live url, got %s", c.BaseURL)
+	}
+	if c.UserAgent != defaultUserAgent {
+		t.Errorf("want default user agent, got %s", c.UserAgent)
+	}
+	if c.Domains == nil || c.Domains.Client != c {
+		t.Errorf("domains service not wired to client")
+	}
+}
+
+func TestNewClientOptions(t *testing.T) {
+	hc := &http.Client{}
+	logger := &recordingLogger{}
+
+	c := NewClient(apiUser, apiKey,
+		WithEnvironment(Test),
+		WithHTTPClient(hc),
+		WithTimeout(5*time.Second),
+		WithUserAgent("my-app/1.0"),
+		WithLogger(logger),
+		WithRetryPolicy(nil),
+	)
+
+	if c.BaseURL != testBaseURL {
+		t.Errorf("want test url, got %s", c.BaseURL)
+	}
+	if c.HttpClient.Timeout != 5*time.Second {
+		t.Errorf("want 5s timeout, got %v", c.HttpClient.Timeout)
+	}
+	if hc.Timeout != 0 {
+		t.Errorf("WithTimeout changed the caller's http.Client")
+	}
+	if c.UserAgent != "my-app/1.0" {
+		t.Errorf("unexpected user agent %s", c.UserAgent)
+	}
+	if c.Logger != logger {
+		t.Errorf("logger not set")
+	}
+	if c.RetryPolicy != nil {
+		t.Errorf("retry policy not cleared")
+	}
+}
+
+func TestWithTimeoutBeforeHTTPClient(t *testing.T) {
+	hc := &http.Client{}
+	c := NewClient(apiUser, apiKey, WithTimeout(5*time.Second), WithHTTPClient(hc))
+	if c.HttpClient.Timeout != 5*time.Second {
+		t.Errorf("want 5s timeout, got %v", c.HttpClient.Timeout)
+	}
+	if hc.Timeout != 0 {
+		t.Errorf("WithTimeout changed the caller's http.Client")
+	}
+
+	c = NewClient(apiUser, apiKey, WithHTTPClient(nil), WithTimeout(time.Second))
+	if c.HttpClient == nil || c.HttpClient.Timeout != time.Second {
+		t.Errorf("want a 1s client in place of nil, got %+v", c.HttpClient)
+	}
+}
+
+func TestUserAgentIsSent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.lookup.example1a.xml")
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != defaultUserAgent {
+			t.Errorf("want user agent %s, got %s", defaultUserAgent, got)
+		}
+		fmt.Fprint(w, respXML)
+	})
+
+	if _, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseEnvironment(t *testing.T) {
+	if env, err := ParseEnvironment(" TEST "); err != nil || env != Test {
+		t.Errorf("want Test, got %v, %v", env, err)
+	}
+	if _, err := ParseEnvironment("staging"); err == nil {
+		t.Errorf("want error for unknown environment")
+	}
+}
+
+func TestWithEnvironmentUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("want a panic for an unknown environment")
+		}
+	}()
+	NewClient("user", "key", WithEnvironment("staging"))
+}
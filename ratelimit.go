@@ -0,0 +1,170 @@
+package opensrs
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit configures a token bucket and a cap on concurrent calls. A zero
+// Rate means no rate limit and a zero MaxInFlight means no concurrency cap.
+type Limit struct {
+	// Rate is the number of calls allowed per second.
+	Rate float64
+	// Burst is the number of calls that may be made at once before Rate
+	// applies. It defaults to 1 when Rate is set.
+	Burst int
+	// MaxInFlight caps the number of calls waiting for a response.
+	MaxInFlight int
+}
+
+// RateLimiter throttles calls made by a Client. Every call has to pass the
+// default limit and, when one is configured, the limit for its action.
+type RateLimiter struct {
+	def      *limiter
+	byAction map[string]*limiter
+}
+
+// NewRateLimiter returns a limiter that applies def to every call and the
+// entries of perAction (keyed by action, e.g. "LOOKUP") to those actions.
+func NewRateLimiter(def Limit, perAction map[string]Limit) *RateLimiter {
+	rl := &RateLimiter{
+		def:      newLimiter(def),
+		byAction: make(map[string]*limiter),
+	}
+	for action, l := range perAction {
+		rl.byAction[strings.ToUpper(action)] = newLimiter(l)
+	}
+	return rl
+}
+
+// acquire blocks until action may be sent or ctx is done. The returned
+// function releases the in-flight slots and must be called once the call is
+// finished. Tokens are taken before any slot, and the action's limit
+// before the default one, so a call throttled by its action never holds a
+// slot that other actions need.
+func (rl *RateLimiter) acquire(ctx context.Context, action string) (func(), error) {
+	if rl == nil {
+		return func() {}, nil
+	}
+
+	byAction := rl.byAction[strings.ToUpper(action)]
+	if err := byAction.wait(ctx); err != nil {
+		return nil, err
+	}
+	if err := rl.def.wait(ctx); err != nil {
+		byAction.refund()
+		return nil, err
+	}
+
+	releaseAction, err := byAction.take(ctx)
+	if err != nil {
+		byAction.refund()
+		rl.def.refund()
+		return nil, err
+	}
+	releaseDef, err := rl.def.take(ctx)
+	if err != nil {
+		releaseAction()
+		byAction.refund()
+		rl.def.refund()
+		return nil, err
+	}
+
+	return func() {
+		releaseDef()
+		releaseAction()
+	}, nil
+}
+
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	slots  chan struct{}
+}
+
+func newLimiter(l Limit) *limiter {
+	if l.Rate <= 0 && l.MaxInFlight <= 0 {
+		return nil
+	}
+	lim := &limiter{rate: l.Rate}
+	if l.Rate > 0 {
+		lim.burst = float64(l.Burst)
+		if lim.burst < 1 {
+			lim.burst = 1
+		}
+		lim.tokens = lim.burst
+	}
+	if l.MaxInFlight > 0 {
+		lim.slots = make(chan struct{}, l.MaxInFlight)
+	}
+	return lim
+}
+
+// take reserves an in-flight slot, blocking until one is free.
+func (l *limiter) take(ctx context.Context) (func(), error) {
+	if l == nil || l.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, CanceledError{Err: ctx.Err()}
+	}
+	return func() { <-l.slots }, nil
+}
+
+// wait takes a token from the bucket, sleeping until one is available.
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.refund()
+		return CanceledError{Err: ctx.Err()}
+	}
+}
+
+// refund gives back a token taken by wait for a call that is not sent.
+func (l *limiter) refund() {
+	if l == nil || l.rate <= 0 {
+		return
+	}
+	l.mu.Lock()
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.mu.Unlock()
+}
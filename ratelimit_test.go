@@ -0,0 +1,161 @@
+package opensrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterMaxInFlight(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RateLimiter = NewRateLimiter(Limit{MaxInFlight: 2}, nil)
+
+	respXML := readFile(t, "testresponses/domain.lookup.example1a.xml")
+
+	var inFlight, peak int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, respXML)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("want at most 2 calls in flight, got %d", peak)
+	}
+}
+
+func TestRateLimiterPerAction(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RateLimiter = NewRateLimiter(Limit{}, map[string]Limit{
+		"LOOKUP": {Rate: 20, Burst: 1},
+	})
+
+	respXML := readFile(t, "testresponses/domain.lookup.example1a.xml")
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, respXML)
+	})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// One call passes on the burst, the other three wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("want calls to be throttled, finished in %v", elapsed)
+	}
+}
+
+func TestRateLimiterThrottledActionHoldsNoSlot(t *testing.T) {
+	rl := NewRateLimiter(Limit{MaxInFlight: 1}, map[string]Limit{
+		"LOOKUP": {Rate: 1, Burst: 1},
+	})
+
+	release, err := rl.acquire(context.Background(), "LOOKUP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	// The second LOOKUP waits about a second for a token.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		release, err := rl.acquire(context.Background(), "LOOKUP")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		release()
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	start := time.Now()
+	release, err = rl.acquire(context.Background(), "GET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("GET waited %v behind a throttled LOOKUP", elapsed)
+	}
+	<-done
+}
+
+func TestRateLimiterHonoursContext(t *testing.T) {
+	rl := NewRateLimiter(Limit{Rate: 1, Burst: 1}, nil)
+
+	release, err := rl.acquire(context.Background(), "LOOKUP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = rl.acquire(ctx, "LOOKUP")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded, got %v", err)
+	}
+
+	var canceled CanceledError
+	if !errors.As(err, &canceled) {
+		t.Errorf("want CanceledError, got %T", err)
+	}
+}
+
+func TestRateLimiterRefundsActionToken(t *testing.T) {
+	rl := NewRateLimiter(Limit{Rate: 1, Burst: 1}, map[string]Limit{
+		"LOOKUP": {Rate: 0.001, Burst: 5},
+	})
+
+	// Use up the default bucket.
+	release, err := rl.acquire(context.Background(), "GET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := rl.acquire(ctx, "LOOKUP"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded, got %v", err)
+	}
+
+	lookup := rl.byAction["LOOKUP"]
+	lookup.mu.Lock()
+	tokens := lookup.tokens
+	lookup.mu.Unlock()
+	if tokens < 4.5 {
+		t.Errorf("a canceled call kept its LOOKUP token, %.2f of 5 left", tokens)
+	}
+}
@@ -0,0 +1,91 @@
+package opensrs
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RawResponse gives access to the parts of a response that the typed
+// responses do not model, such as TLD specific attributes.
+type RawResponse struct {
+	// XML is the response body as it was received.
+	XML []byte
+
+	root *Item
+	once sync.Once
+	m    Map
+}
+
+// Map returns the data block decoded as a Map. It is built on first use.
+func (r *RawResponse) Map() Map {
+	r.once.Do(func() {
+		if r.root == nil {
+			return
+		}
+		r.m, _ = r.root.decode().(Map)
+	})
+	return r.m
+}
+
+// Get returns the value at path in the data block, see Map.Get.
+func (r *RawResponse) Get(path string) (interface{}, bool) {
+	return r.Map().Get(path)
+}
+
+// GetString returns the string at path in the data block, see Map.Get.
+func (r *RawResponse) GetString(path string) (string, bool) {
+	return r.Map().GetString(path)
+}
+
+// setRaw lets the decoder attach the raw response to any type that embeds
+// BaseResponse.
+func (r *BaseResponse) setRaw(raw *RawResponse) {
+	r.Raw = raw
+}
+
+// Get returns the value at path, a slash separated list of keys such as
+// "attributes/lookup/items/0/domain". Array elements are addressed by
+// their index.
+func (m Map) Get(path string) (interface{}, bool) {
+	var v interface{} = m
+	for _, key := range strings.Split(strings.Trim(path, "/"), "/") {
+		switch c := v.(type) {
+		case Map:
+			var ok bool
+			if v, ok = c[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			v = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// GetString is Get for scalar values.
+func (m Map) GetString(path string) (string, bool) {
+	v, ok := m.Get(path)
+	s, isString := v.(string)
+	return s, ok && isString
+}
+
+// GetMap is Get for dt_assoc values.
+func (m Map) GetMap(path string) (Map, bool) {
+	v, ok := m.Get(path)
+	sub, isMap := v.(Map)
+	return sub, ok && isMap
+}
+
+// GetSlice is Get for dt_array values.
+func (m Map) GetSlice(path string) ([]interface{}, bool) {
+	v, ok := m.Get(path)
+	s, isSlice := v.([]interface{})
+	return s, ok && isSlice
+}
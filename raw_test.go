@@ -0,0 +1,100 @@
+package opensrs
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestResponseRaw(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.namesuggest.example1.xml")
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, respXML)
+	})
+
+	resp, err := client.Domains.NameSuggest(NameSuggestRequestAttributes{SearchString: "search string"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Raw == nil {
+		t.Fatal("resp.Raw is nil")
+	}
+	if string(resp.Raw.XML) != respXML {
+		t.Errorf("resp.Raw.XML is not the response body")
+	}
+
+	if got, _ := resp.Raw.GetString("attributes/lookup/items/0/domain"); got != resp.Attributes.Lookup.Items[0].Domain {
+		t.Errorf("got domain %q, want %q", got, resp.Attributes.Lookup.Items[0].Domain)
+	}
+	if got, _ := resp.Raw.GetString("request_response_time"); got != resp.RequestResponseTime {
+		t.Errorf("got request_response_time %q, want %q", got, resp.RequestResponseTime)
+	}
+	if _, ok := resp.Raw.Get("attributes/no_such_key"); ok {
+		t.Errorf("want no value for a missing key")
+	}
+}
+
+func TestErrorResponseRaw(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, failedResponseXML, "465", "Invalid attribute value")
+	})
+
+	_, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	e, ok := err.(ErrorResponse)
+	if !ok || e.OpenSRSResponse == nil || e.OpenSRSResponse.Raw == nil {
+		t.Fatalf("want the raw response on the error, got %#v", err)
+	}
+	if got, _ := e.OpenSRSResponse.Raw.GetString("response_code"); got != "465" {
+		t.Errorf("got response_code %q, want 465", got)
+	}
+}
+
+func TestMapGet(t *testing.T) {
+	m := Map{
+		"attributes": Map{
+			"status": "active",
+			"nameserver_list": []interface{}{
+				Map{"name": "ns1.example.com"},
+				Map{"name": "ns2.example.com"},
+			},
+		},
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+		ok   bool
+	}{
+		{"attributes/status", "active", true},
+		{"/attributes/status/", "active", true},
+		{"attributes/nameserver_list/1/name", "ns2.example.com", true},
+		{"attributes/nameserver_list/2/name", nil, false},
+		{"attributes/nameserver_list/x", nil, false},
+		{"attributes/status/deeper", nil, false},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := m.Get(tt.path)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Get(%q) = %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, ok := m.GetString("attributes"); ok {
+		t.Errorf("GetString on a dt_assoc should fail")
+	}
+	if sub, ok := m.GetMap("attributes"); !ok || sub["status"] != "active" {
+		t.Errorf("GetMap(attributes) = %v, %v", sub, ok)
+	}
+	if s, ok := m.GetSlice("attributes/nameserver_list"); !ok || len(s) != 2 {
+		t.Errorf("GetSlice(attributes/nameserver_list) = %v, %v", s, ok)
+	}
+}
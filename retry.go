@@ -0,0 +1,156 @@
+package opensrs
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how Client.Do retries a failed call. A nil policy
+// disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// BaseDelay is the wait before the second attempt. It doubles for every
+	// following attempt, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Jitter is the fraction (0 to 1) of each delay that is randomized.
+	Jitter float64
+	// Retryable decides whether a failed attempt is retried. When nil,
+	// DefaultRetryable is used.
+	Retryable func(a RetryAttempt) bool
+}
+
+// RetryAttempt describes a failed attempt to the retry classifier.
+type RetryAttempt struct {
+	Action  string
+	Object  string
+	Attempt int
+	// Err is the error the attempt produced.
+	Err error
+	// HttpResponse and Response are set when the attempt got that far.
+	HttpResponse *http.Response
+	Response     *BaseResponse
+}
+
+// DefaultRetryPolicy returns the policy NewClient installs: up to three
+// attempts of read-only actions, starting with a 200ms backoff.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// readOnlyActions are the actions that never change state at OpenSRS, so
+// sending them twice is harmless.
+var readOnlyActions = map[string]bool{
+	"LOOKUP":               true,
+	"NAME_SUGGEST":         true,
+	"GET":                  true,
+	"GET_DOMAINS_CONTACTS": true,
+	"GET_PRICE":            true,
+	"BELONGS_TO_RSP":       true,
+	"CHECK_TRANSFER":       true,
+	"GET_BALANCE":          true,
+}
+
+// IsReadOnlyAction reports whether action is known not to change state.
+func IsReadOnlyAction(action string) bool {
+	return readOnlyActions[strings.ToUpper(action)]
+}
+
+// DefaultRetryable retries read-only actions that failed because of a
+// network error, a 5xx HTTP status or a temporary OpenSRS condition.
+// Mutating actions are never retried, so a retry cannot register or renew a
+// domain twice.
+func DefaultRetryable(a RetryAttempt) bool {
+	if !IsReadOnlyAction(a.Action) {
+		return false
+	}
+
+	var canceled CanceledError
+	if errors.As(a.Err, &canceled) {
+		return false
+	}
+
+	if a.Response != nil {
+		text := strings.ToLower(a.Response.ResponseText)
+		return strings.Contains(text, "timeout") ||
+			strings.Contains(text, "timed out") ||
+			strings.Contains(text, "try again")
+	}
+
+	if a.HttpResponse != nil {
+		return a.HttpResponse.StatusCode >= 500
+	}
+
+	// No response at all, the request failed in transport.
+	return true
+}
+
+func (p *RetryPolicy) retry(a RetryAttempt) bool {
+	if p == nil || a.Attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(a)
+	}
+	return DefaultRetryable(a)
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// wait sleeps for the backoff that follows attempt, or until ctx is done.
+func (p *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	t := time.NewTimer(p.backoff(attempt))
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return CanceledError{Err: ctx.Err()}
+	}
+}
+
+// rewindable reports whether req can be sent again. A body without GetBody
+// has been drained by the first attempt.
+func rewindable(req *http.Request) bool {
+	return req.GetBody != nil || req.Body == nil || req.Body == http.NoBody
+}
+
+// rewindRequest returns a copy of req with a fresh body, so it can be sent
+// again. The signature only depends on the body and stays valid.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if !rewindable(req) {
+		return nil, errors.New("opensrs: request body cannot be rewound")
+	}
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
@@ -0,0 +1,163 @@
+package opensrs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}
+}
+
+func TestRetryReadOnlyActionOn5xx(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+
+	respXML := readFile(t, "testresponses/domain.lookup.example1a.xml")
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		testAuth(t, r.Header, string(readBody(t, r)))
+		fmt.Fprint(w, respXML)
+	})
+
+	resp, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("want 3 calls, got %d", calls)
+	}
+	if resp.Attributes.Status != "available" {
+		t.Errorf("unexpected status %q", resp.Attributes.Status)
+	}
+}
+
+func TestRetryStopsWithoutGetBody(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	req, err := client.NewRequest("POST", "", LookupRequest{
+		BaseRequest: BaseRequest{Action: "LOOKUP", Object: "DOMAIN", Protocol: "XCP"},
+		Attributes:  LookupRequestAttributes{Domain: "example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := req.GetBody()
+	req.Body = ioutil.NopCloser(body)
+	req.GetBody = nil
+
+	err = client.Do(req, &LookupResponse{})
+	if !errors.Is(err, ErrUnexpectedStatus) {
+		t.Errorf("want ErrUnexpectedStatus, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("a drained body must not be sent again, got %d calls", calls)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	_, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	if !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("want ErrUnexpectedStatus, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("want 3 calls, got %d", calls)
+	}
+}
+
+func TestRetrySkipsMutatingActions(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	req, err := client.NewRequest("POST", "", BaseRequest{
+		Action:   "SW_REGISTER",
+		Object:   "DOMAIN",
+		Protocol: "XCP",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Do(req, &BaseResponse{})
+	if !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("want ErrUnexpectedStatus, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("want 1 call, got %d", calls)
+	}
+}
+
+func TestRetryClassifierSeesResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var seen []RetryAttempt
+	client.RetryPolicy = fastRetryPolicy()
+	client.RetryPolicy.Retryable = func(a RetryAttempt) bool {
+		seen = append(seen, a)
+		return a.Response != nil && a.Response.ResponseCode == "705"
+	}
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			fmt.Fprintf(w, failedResponseXML, "705", "Registry busy")
+			return
+		}
+		fmt.Fprintf(w, failedResponseXML, "465", "Invalid attribute value")
+	})
+
+	_, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	if !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("want ErrInvalidData, got %v", err)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("want classifier to run twice, got %d", len(seen))
+	}
+	if seen[0].Action != "LOOKUP" || seen[0].Object != "DOMAIN" || seen[0].Attempt != 1 {
+		t.Errorf("unexpected first attempt %+v", seen[0])
+	}
+	if seen[1].HttpResponse == nil || seen[1].Response.ResponseCode != "465" {
+		t.Errorf("unexpected second attempt %+v", seen[1])
+	}
+}
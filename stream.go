@@ -0,0 +1,317 @@
+package opensrs
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrPathNotFound is returned by ArrayStream when the envelope has no
+// dt_array at the requested path, which is the case for failed commands.
+var ErrPathNotFound = errors.New("opensrs: path not found in response")
+
+// ArrayStream reads the elements of one dt_array from an OPS envelope
+// without building the rest of the tree. Only the element being returned
+// is held in memory.
+type ArrayStream struct {
+	d    *xml.Decoder
+	path []string
+	keys []string
+	base BaseResponse
+
+	inArray bool
+	found   bool
+	index   int
+	done    bool
+}
+
+// NewArrayStream returns a stream over the dt_array found at path, a
+// slash-separated list of item keys below the data block such as
+// "attributes/suggestion/items".
+func NewArrayStream(r io.Reader, path string) *ArrayStream {
+	s := &ArrayStream{d: xml.NewDecoder(r)}
+	// An empty path selects a dt_array that is the data block itself.
+	if path = strings.Trim(path, "/"); path != "" {
+		s.path = strings.Split(path, "/")
+	}
+	return s
+}
+
+// Next decodes the next array element into v, which must be a non-nil
+// pointer. It returns io.EOF after the last element and ErrPathNotFound if
+// the envelope ends without the array.
+func (s *ArrayStream) Next(v interface{}) error {
+	item, err := s.NextItem()
+	if err != nil {
+		return err
+	}
+	return item.Decode(v)
+}
+
+// NextItem is like Next but returns the raw element.
+func (s *ArrayStream) NextItem() (*Item, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+
+	for {
+		tok, err := s.d.Token()
+		if err == io.EOF {
+			s.done = true
+			return nil, ErrPathNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "item":
+				if s.inArray {
+					item := &Item{}
+					if err := s.d.DecodeElement(item, &t); err != nil {
+						return nil, err
+					}
+					s.index++
+					return item, nil
+				}
+
+				key := attr(t, "key")
+				if len(s.keys) == 0 {
+					if f, ok := lookupField(cachedFields(baseResponseType), key); ok {
+						item := &Item{}
+						if err := s.d.DecodeElement(item, &t); err != nil {
+							return nil, err
+						}
+						decodeItem(item, fieldByIndex(reflect.ValueOf(&s.base).Elem(), f.index))
+						continue
+					}
+				}
+				s.keys = append(s.keys, key)
+			case "dt_array":
+				if !s.found && pathEqual(s.keys, s.path) {
+					s.inArray, s.found = true, true
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "item":
+				if len(s.keys) > 0 {
+					s.keys = s.keys[:len(s.keys)-1]
+				}
+			case "dt_array":
+				if s.inArray {
+					s.inArray = false
+					s.done = true
+					return nil, io.EOF
+				}
+			}
+		}
+	}
+}
+
+// Base returns the base fields seen so far. OpenSRS may send some of them
+// after the attributes, call Finish to read them all.
+func (s *ArrayStream) Base() BaseResponse {
+	return s.base
+}
+
+// Finish skips the remaining elements and the rest of the envelope and
+// returns all of its base fields.
+func (s *ArrayStream) Finish() (*BaseResponse, error) {
+	for {
+		_, err := s.NextItem()
+		if err == io.EOF || err == ErrPathNotFound {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Walk the rest of the envelope for base fields sent after the array.
+	s.done = false
+	if _, err := s.NextItem(); err != ErrPathNotFound {
+		return nil, err
+	}
+	base := s.base
+	return &base, nil
+}
+
+// Count returns the number of elements returned so far.
+func (s *ArrayStream) Count() int {
+	return s.index
+}
+
+// StreamItems calls fn for every element of the dt_array at path and
+// returns the base fields of the envelope.
+func StreamItems(r io.Reader, path string, fn func(item *Item) error) (*BaseResponse, error) {
+	s := NewArrayStream(r, path)
+	for {
+		item, err := s.NextItem()
+		if err == io.EOF {
+			break
+		}
+		if err == ErrPathNotFound {
+			// The whole envelope has been read, so the base fields are
+			// complete and tell why the array is missing.
+			base := s.Base()
+			return &base, err
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(item); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.Finish()
+}
+
+// ExecuteStream sends a command like Execute, but streams the elements of
+// the dt_array at path to fn instead of decoding the whole reply. The call
+// is logged through the client's Logger with the same fields as other
+// calls, but the reply body is not, as it is never held in memory. It
+// does not pass through the interceptors, is not retried, and the reply
+// is decoded leniently whatever the client's StrictMode.
+func (c *Client) ExecuteStream(ctx context.Context, object, action string, attributes interface{}, path string, fn func(item *Item) error) (*BaseResponse, error) {
+	if CorrelationID(ctx) == "" {
+		ctx = WithCorrelationID(ctx, newCorrelationID())
+	}
+	payload := executeRequest{
+		BaseRequest: BaseRequest{
+			Action:   action,
+			Object:   object,
+			Protocol: "XCP",
+		},
+		Attributes: attributes,
+	}
+	req, err := c.NewRequestWithContext(ctx, "POST", "", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := c.logger()
+	fields := []Field{
+		{"action", action},
+		{"object", object},
+		{"correlation_id", CorrelationID(ctx)},
+	}
+
+	start := time.Now()
+	base, err := c.stream(req, path, fn, logger, fields)
+	outcome := append(fields,
+		Field{"attempt", 1},
+		Field{"latency", time.Since(start)},
+	)
+	if base != nil {
+		outcome = append(outcome, Field{"response_code", base.ResponseCode})
+	}
+	if err != nil {
+		logger.Log(LevelError, "opensrs call failed", append(outcome, Field{"error", err})...)
+		return base, err
+	}
+	logger.Log(LevelInfo, "opensrs call succeeded", outcome...)
+	return base, nil
+}
+
+// stream makes the single HTTP attempt of ExecuteStream.
+func (c *Client) stream(req *http.Request, path string, fn func(item *Item) error, logger Logger, fields []Field) (*BaseResponse, error) {
+	ctx := req.Context()
+	release, err := c.RateLimiter.acquire(ctx, requestBase(req).Action)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if _, ok := logger.(nopLogger); !ok {
+		redactor := c.redactor()
+		var envelope []byte
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				envelope, _ = ioutil.ReadAll(body)
+			}
+		}
+		logger.Log(LevelDebug, "opensrs request", append(fields,
+			Field{"url", req.URL},
+			Field{"header", redactor.RedactHeader(req.Header)},
+			Field{"body", string(redactor.RedactXML(envelope))},
+		)...)
+	}
+
+	e := ErrorResponse{}
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, CanceledError{Err: ctxErr}
+		}
+		e.Err = err
+		return nil, e
+	}
+	defer resp.Body.Close()
+	e.HttpResponse = resp
+
+	logger.Log(LevelDebug, "opensrs response", append(fields,
+		Field{"http_status", resp.StatusCode},
+	)...)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		e.Err = fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+		return nil, e
+	}
+
+	base, err := StreamItems(resp.Body, path, fn)
+	if base != nil && base.IsSuccess != true {
+		e.OpenSRSResponse = base
+		e.Err = responseError(base)
+		return base, e
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return base, CanceledError{Err: ctxErr}
+		}
+		return base, err
+	}
+	return base, nil
+}
+
+// Decode stores the content of the item in v, which must be a non-nil
+// pointer.
+func (i *Item) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("opensrs: cannot decode into non-pointer %T", v)
+	}
+	return decodeItem(i, rv.Elem())
+}
+
+var baseResponseType = reflect.TypeOf(BaseResponse{})
+
+func attr(se xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func pathEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package opensrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestArrayStream(t *testing.T) {
+	respXML := readFile(t, "testresponses/domain.namesuggest.example1.xml")
+
+	var full NameSuggestResponse
+	if err := FromXml([]byte(respXML), &full); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewArrayStream(strings.NewReader(respXML), "attributes/suggestion/items")
+	var got []NameSuggestItem
+	for {
+		var item NameSuggestItem
+		err := s.Next(&item)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, item)
+	}
+
+	want := full.Attributes.Suggestion.Items
+	if len(got) != len(want) || s.Count() != len(want) {
+		t.Fatalf("want %d items, got %d (count %d)", len(want), len(got), s.Count())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("item %d, got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if err := s.Next(&NameSuggestItem{}); err != io.EOF {
+		t.Errorf("want io.EOF after the last element, got %v", err)
+	}
+
+	// is_success follows the attributes in this reply.
+	base, err := s.Finish()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base.ResponseCode != full.ResponseCode || base.IsSuccess != true || base.Protocol != "XCP" {
+		t.Errorf("unexpected base %+v", base)
+	}
+}
+
+func TestStreamItemsPathNotFound(t *testing.T) {
+	body := fmt.Sprintf(failedResponseXML, "465", "Invalid attribute value")
+
+	base, err := StreamItems(strings.NewReader(body), "attributes/suggestion/items", func(item *Item) error {
+		t.Error("callback must not be called")
+		return nil
+	})
+	if err != ErrPathNotFound {
+		t.Fatalf("want ErrPathNotFound, got %v", err)
+	}
+	if base.ResponseCode != "465" {
+		t.Errorf("want base fields of the failed reply, got %+v", base)
+	}
+}
+
+func TestExecuteStream(t *testing.T) {
+	setup()
+	defer teardown()
+
+	respXML := readFile(t, "testresponses/domain.namesuggest.example1.xml")
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		testAuth(t, r.Header, string(readBody(t, r)))
+		fmt.Fprint(w, respXML)
+	})
+
+	var domains []string
+	base, err := client.ExecuteStream(context.Background(), "DOMAIN", "NAME_SUGGEST",
+		Map{"searchstring": "search string"}, "attributes/lookup/items",
+		func(item *Item) error {
+			var v NameSuggestItem
+			if err := item.Decode(&v); err != nil {
+				return err
+			}
+			domains = append(domains, v.Domain)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base.ResponseCode != "200" {
+		t.Errorf("unexpected base %+v", base)
+	}
+	if len(domains) != 5 || domains[0] != "searchstring.com" {
+		t.Errorf("unexpected domains %v", domains)
+	}
+}
+
+func TestExecuteStreamFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, failedResponseXML, "415", "Authentication failed")
+	})
+
+	_, err := client.ExecuteStream(context.Background(), "DOMAIN", "GET", nil, "attributes/domain_list", func(*Item) error {
+		return nil
+	})
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("want ErrAuthentication, got %v", err)
+	}
+}
+
+func TestExecuteStreamLogs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	logger := &recordingLogger{}
+	client.Logger = logger
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, failedResponseXML, "415", "Authentication failed")
+	})
+
+	ctx := WithCorrelationID(context.Background(), "stream-1")
+	client.ExecuteStream(ctx, "DOMAIN", "GET", Map{"reg_password": "s3cret"}, "attributes/domain_list", func(*Item) error {
+		return nil
+	})
+
+	var failed, request bool
+	for _, e := range logger.entries {
+		if e.fields["correlation_id"] != "stream-1" || e.fields["action"] != "GET" {
+			t.Errorf("unexpected fields %v", e.fields)
+		}
+		switch e.msg {
+		case "opensrs request":
+			request = true
+			if body := fmt.Sprint(e.fields["body"]); strings.Contains(body, "s3cret") {
+				t.Errorf("request body not redacted: %s", body)
+			}
+		case "opensrs call failed":
+			failed = e.level == LevelError && e.fields["response_code"] == "415"
+		}
+	}
+	if !request || !failed {
+		t.Errorf("want the request and the failure logged, got %+v", logger.entries)
+	}
+}
@@ -0,0 +1,59 @@
+package opensrs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StrictMode controls how the client reacts to response items that the
+// target type has no field for, or whose shape does not fit the field.
+type StrictMode int
+
+const (
+	// StrictOff silently leaves such items out, which is the default.
+	StrictOff StrictMode = iota
+	// StrictWarn logs them at LevelWarn and carries on.
+	StrictWarn
+	// StrictError fails read-only calls with a *StrictDecodeError. Calls
+	// that change state, such as SW_REGISTER or RENEW, are handled as in
+	// StrictWarn so their result is never lost.
+	StrictError
+)
+
+func (m StrictMode) String() string {
+	switch m {
+	case StrictOff:
+		return "off"
+	case StrictWarn:
+		return "warn"
+	case StrictError:
+		return "error"
+	}
+	return fmt.Sprintf("StrictMode(%d)", int(m))
+}
+
+// DecodeIssue is a response item that was left out while decoding.
+type DecodeIssue struct {
+	// Path is the slash separated key path of the item, e.g.
+	// "attributes/lookup/items".
+	Path   string
+	Reason string
+}
+
+func (i DecodeIssue) String() string {
+	return i.Path + ": " + i.Reason
+}
+
+// StrictDecodeError is returned in strict mode when a response holds items
+// that the target type cannot represent. The target is still decoded.
+type StrictDecodeError struct {
+	Issues []DecodeIssue
+}
+
+func (e *StrictDecodeError) Error() string {
+	s := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		s[i] = issue.String()
+	}
+	return "opensrs: strict decode: " + strings.Join(s, "; ")
+}
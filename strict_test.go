@@ -0,0 +1,187 @@
+package opensrs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const driftedLookupXML = `<?xml version='1.0' encoding='UTF-8' standalone='no' ?>
+<!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'>
+<OPS_envelope><header><version>0.9</version></header><body><data_block><dt_assoc>
+<item key="protocol">XCP</item>
+<item key="action">REPLY</item>
+<item key="object">DOMAIN</item>
+<item key="is_success">1</item>
+<item key="response_code">210</item>
+<item key="response_text">Domain available</item>
+<item key="attributes"><dt_assoc>
+<item key="status">available</item>
+<item key="reason"><dt_assoc><item key="code">1</item></dt_assoc></item>
+<item key="premium_price">12.00</item>
+</dt_assoc></item>
+</dt_assoc></data_block></body></OPS_envelope>`
+
+var driftedLookupIssues = []DecodeIssue{
+	{Path: "attributes/reason", Reason: "unexpected dt_assoc for string"},
+	{Path: "attributes/premium_price", Reason: "unknown key for opensrs.LookupResponseAttributes"},
+}
+
+func TestFromXmlStrict(t *testing.T) {
+	var resp LookupResponse
+	err := FromXmlStrict([]byte(driftedLookupXML), &resp)
+
+	var strictErr *StrictDecodeError
+	if !errors.As(err, &strictErr) {
+		t.Fatalf("want *StrictDecodeError, got %v", err)
+	}
+	if !reflect.DeepEqual(strictErr.Issues, driftedLookupIssues) {
+		t.Errorf("got issues %+v, want %+v", strictErr.Issues, driftedLookupIssues)
+	}
+	if resp.Attributes.Status != "available" {
+		t.Errorf("want the known fields decoded, got %+v", resp.Attributes)
+	}
+
+	if err := FromXml([]byte(driftedLookupXML), &resp); err != nil {
+		t.Errorf("lenient decode: unexpected error %v", err)
+	}
+}
+
+func TestFromXmlStrictArrayPath(t *testing.T) {
+	var v struct {
+		Items []struct {
+			Domain string `opensrs:"domain"`
+		} `opensrs:"items"`
+	}
+	b := envelope(`<dt_assoc><item key="items"><dt_array>` +
+		`<item key="0"><dt_assoc><item key="domain">a.com</item></dt_assoc></item>` +
+		`<item key="1"><dt_assoc><item key="domain">b.com</item><item key="tld">com</item></dt_assoc></item>` +
+		`</dt_array></item></dt_assoc>`)
+
+	err := FromXmlStrict([]byte(b), &v)
+	var strictErr *StrictDecodeError
+	if !errors.As(err, &strictErr) || len(strictErr.Issues) != 1 {
+		t.Fatalf("want one issue, got %v", err)
+	}
+	if got := strictErr.Issues[0].Path; got != "items/1/tld" {
+		t.Errorf("got path %q, want items/1/tld", got)
+	}
+}
+
+// TestResponseFixturesStrict guards the response types against drift in
+// the recorded responses.
+func TestResponseFixturesStrict(t *testing.T) {
+	targets := map[string]func() interface{}{
+		"domain.get.":         func() interface{} { return &GetResponse{} },
+		"domain.lookup.":      func() interface{} { return &LookupResponse{} },
+		"domain.modify.":      func() interface{} { return &ModifyResponse{} },
+		"domain.namesuggest.": func() interface{} { return &NameSuggestResponse{} },
+		"domain.renew.":       func() interface{} { return &RenewResponse{} },
+		"domain.sw_register.": func() interface{} { return &RegisterResponse{} },
+	}
+
+	files, err := filepath.Glob("testresponses/*.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		for prefix, target := range targets {
+			if !strings.HasPrefix(filepath.Base(file), prefix) {
+				continue
+			}
+			if err := FromXmlStrict([]byte(readFile(t, file)), target()); err != nil {
+				t.Errorf("%s: %v", file, err)
+			}
+		}
+	}
+}
+
+func TestClientStrictError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Strict = StrictError
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, driftedLookupXML)
+	})
+
+	_, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+
+	var strictErr *StrictDecodeError
+	if !errors.As(err, &strictErr) {
+		t.Fatalf("want *StrictDecodeError, got %v", err)
+	}
+	if len(strictErr.Issues) != len(driftedLookupIssues) {
+		t.Errorf("got issues %+v, want %+v", strictErr.Issues, driftedLookupIssues)
+	}
+	if errors.Is(err, ErrCommandFailed) {
+		t.Errorf("a strict decode error is not a failed command")
+	}
+}
+
+func TestClientStrictWarn(t *testing.T) {
+	setup()
+	defer teardown()
+
+	logger := &recordingLogger{}
+	client.Logger = logger
+	client.Strict = StrictWarn
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, driftedLookupXML)
+	})
+
+	resp, err := client.Domains.Lookup(LookupRequestAttributes{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attributes.Status != "available" {
+		t.Errorf("unexpected status %q", resp.Attributes.Status)
+	}
+
+	var paths []string
+	for _, e := range logger.entries {
+		if e.level == LevelWarn && e.msg == "opensrs response item not decoded" {
+			paths = append(paths, fmt.Sprint(e.fields["path"]))
+		}
+	}
+	want := []string{"attributes/reason", "attributes/premium_price"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("warned about %v, want %v", paths, want)
+	}
+}
+
+func TestClientStrictErrorKeepsMutatingResult(t *testing.T) {
+	setup()
+	defer teardown()
+
+	logger := &recordingLogger{}
+	client.Logger = logger
+	client.Strict = StrictError
+	respXML := strings.Replace(readFile(t, "testresponses/domain.sw_register.example1.xml"),
+		`<item key="id">`, `<item key="new_field">x</item><item key="id">`, 1)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, respXML)
+	})
+
+	resp, err := client.Domains.Register(RegisterRequestAttributes{Domain: "example.com", RegType: RegTypeNew})
+	if err != nil {
+		t.Fatalf("a registered domain must not fail the call: %v", err)
+	}
+	if resp.Attributes.OrderID != "3735281" {
+		t.Errorf("unexpected order ID %q", resp.Attributes.OrderID)
+	}
+
+	var warned bool
+	for _, e := range logger.entries {
+		if e.level == LevelWarn && e.fields["path"] == "attributes/new_field" {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("want a warning for attributes/new_field, got %+v", logger.entries)
+	}
+}
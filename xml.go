@@ -1,36 +1,81 @@
 package opensrs
 
 import (
-	"bytes"
-	"encoding/json"
 	"encoding/xml"
-	"errors"
-	"log"
+	"fmt"
 	"reflect"
-	"strconv"
-	"strings"
 )
 
+// The types below mirror the elements of ops.dtd.
+
 type Header struct {
 	XMLName xml.Name `xml:"header"`
 	Version string   `xml:"version"`
+	MsgID   string   `xml:"msg_id,omitempty"`
+	MsgType string   `xml:"msg_type,omitempty"`
 }
 
 type Item struct {
-	XMLName xml.Name `xml:"item"`
-	Key     string   `xml:"key,attr"`
-	DtArray *DtArray `xml:"dt_array,omitempty"`
-	DtAssoc *DtAssoc `xml:"dt_assoc,omitempty"`
-	Value   string   `xml:",chardata"`
+	XMLName     xml.Name     `xml:"item"`
+	Key         string       `xml:"key,attr"`
+	Class       string       `xml:"class,attr,omitempty"`
+	DtArray     *DtArray     `xml:"dt_array,omitempty"`
+	DtAssoc     *DtAssoc     `xml:"dt_assoc,omitempty"`
+	DtScalar    *DtScalar    `xml:"dt_scalar,omitempty"`
+	DtScalarRef *DtScalarRef `xml:"dt_scalarref,omitempty"`
+	DtObject    *DtObject    `xml:"dt_object,omitempty"`
+	Value       string       `xml:",chardata"`
 }
 
 func (i *Item) decode() interface{} {
-	if i.DtAssoc != nil {
-		return i.DtAssoc.decode()
+	if a := i.assoc(); a != nil {
+		return a.decode()
 	}
 	if i.DtArray != nil {
 		return i.DtArray.decode()
 	}
+	return i.text()
+}
+
+// assoc returns the key/value content of the item, from either a dt_assoc
+// or a dt_object.
+func (i *Item) assoc() *DtAssoc {
+	if i.DtAssoc != nil {
+		return i.DtAssoc
+	}
+	if i.DtObject != nil {
+		return i.DtObject.assoc()
+	}
+	return nil
+}
+
+// isScalar reports whether the item holds text rather than a structure.
+func (i *Item) isScalar() bool {
+	return i.DtAssoc == nil && i.DtArray == nil && i.DtObject == nil
+}
+
+// kind names the form of the item, for error messages.
+func (i *Item) kind() string {
+	switch {
+	case i.DtAssoc != nil:
+		return "dt_assoc"
+	case i.DtArray != nil:
+		return "dt_array"
+	case i.DtObject != nil:
+		return "dt_object"
+	}
+	return "scalar"
+}
+
+// text returns the scalar content of the item, which may be wrapped in a
+// dt_scalar or dt_scalarref.
+func (i *Item) text() string {
+	if i.DtScalar != nil {
+		return i.DtScalar.Value
+	}
+	if i.DtScalarRef != nil {
+		return i.DtScalarRef.Value
+	}
 	return i.Value
 }
 
@@ -60,91 +105,56 @@ func (d *DtAssoc) decode() Map {
 	return m
 }
 
-type DataBlock struct {
-	XMLName xml.Name `xml:"data_block"`
-	DtAssoc *DtAssoc `xml:"dt_assoc,omitempty"`
-	//DtArray DtArray `xml:"dt_array,omitempty"`
+type DtScalar struct {
+	XMLName xml.Name `xml:"dt_scalar"`
+	Value   string   `xml:",chardata"`
 }
 
-type Map map[string]interface{}
+type DtScalarRef struct {
+	XMLName xml.Name `xml:"dt_scalarref"`
+	Value   string   `xml:",chardata"`
+}
 
-func (d *DataBlock) decode() Map {
-	m := make(Map)
-	if d.DtAssoc != nil {
-		return d.DtAssoc.decode()
-	}
-	return m
+// DtObject is a serialized object. Its properties are either listed
+// directly or wrapped in a dt_assoc.
+type DtObject struct {
+	XMLName  xml.Name `xml:"dt_object"`
+	Class    string   `xml:"class,attr,omitempty"`
+	DtAssoc  *DtAssoc `xml:"dt_assoc,omitempty"`
+	ItemList []Item   `xml:"item,omitempty"`
 }
 
-func encodeItem(key string, value reflect.Value) Item {
-	item := Item{}
-	item.Key = key
-	v := internalEncode(value)
-	s, ok := v.(string)
-	if ok {
-		item.Value = s
+func (o *DtObject) assoc() *DtAssoc {
+	if o.DtAssoc != nil {
+		return o.DtAssoc
 	}
-	dtass, ok := v.(DtAssoc)
-	if ok {
-		item.DtAssoc = &dtass
-	}
-	dtarr, ok := v.(DtArray)
-	if ok {
-		item.DtArray = &dtarr
-	}
-	return item
+	return &DtAssoc{ItemList: o.ItemList}
+}
+
+type DataBlock struct {
+	XMLName     xml.Name     `xml:"data_block"`
+	DtAssoc     *DtAssoc     `xml:"dt_assoc,omitempty"`
+	DtArray     *DtArray     `xml:"dt_array,omitempty"`
+	DtScalar    *DtScalar    `xml:"dt_scalar,omitempty"`
+	DtScalarRef *DtScalarRef `xml:"dt_scalarref,omitempty"`
+	DtObject    *DtObject    `xml:"dt_object,omitempty"`
 }
 
-func internalEncode(v reflect.Value) (p interface{}) {
-	if !v.IsValid() {
+type Map map[string]interface{}
+
+// item returns the content of the data block as an item, so it can be
+// decoded like any nested one. It returns nil for an empty data block.
+func (d *DataBlock) item() *Item {
+	if d.DtAssoc == nil && d.DtArray == nil && d.DtScalar == nil && d.DtScalarRef == nil && d.DtObject == nil {
 		return nil
 	}
-
-	t := v.Type()
-	switch t.Kind() {
-	case reflect.Interface:
-		return internalEncode(v.Elem())
-	case reflect.String:
-		return v.Interface().(string)
-	case reflect.Struct:
-		dt := DtAssoc{}
-		for i := 0; i < t.NumField(); i++ {
-			key := strings.ToLower(t.Field(i).Name)
-			value := v.Field(i)
-			item := encodeItem(key, value)
-			dt.ItemList = append(dt.ItemList, item)
-		}
-		return dt
-	case reflect.Map: // DtAssoc
-		dt := DtAssoc{}
-		for _, k := range v.MapKeys() {
-			v := v.MapIndex(k)
-			key := k.String()
-			item := encodeItem(key, v)
-			dt.ItemList = append(dt.ItemList, item)
-
-		}
-		return dt
-	case reflect.Slice: //DtArray
-		dt := DtArray{}
-		for i := 0; i < v.Len(); i++ {
-			key := strconv.Itoa(i)
-			value := v.Index(i)
-			item := encodeItem(key, value)
-			dt.ItemList = append(dt.ItemList, item)
-		}
-		return dt
-
-	case reflect.Bool:
-		value := v.Interface().(bool)
-		if value == true {
-			return "1"
-		}
-		return "0"
-	default:
-		log.Println("FAIL, unknown type", t.Kind())
+	return &Item{
+		DtAssoc:     d.DtAssoc,
+		DtArray:     d.DtArray,
+		DtScalar:    d.DtScalar,
+		DtScalarRef: d.DtScalarRef,
+		DtObject:    d.DtObject,
 	}
-	return nil
 }
 
 type Body struct {
@@ -158,36 +168,44 @@ type OPSEnvelope struct {
 	Body    Body     `xml:"body"`
 }
 
+// FromXml decodes the data block of an OPS envelope into v, which must be a
+// non-nil pointer. Struct fields are matched by their `opensrs` tags.
 func FromXml(b []byte, v interface{}) error {
-	var q OPSEnvelope
-	err := xml.Unmarshal(b, &q)
+	r, err := parseResponse(b)
 	if err != nil {
 		return err
 	}
-	m := q.Body.DataBlock.decode()
-
-	j := &bytes.Buffer{}
-	enc := json.NewEncoder(j)
-	enc.SetEscapeHTML(false)
-	enc.Encode(m)
-	jsonString := j.Bytes()
-	//jsonString, _ := json.Marshal(m)
-
-	return json.Unmarshal(jsonString, &v)
+	return r.decode(v)
+}
 
+// FromXmlStrict is like FromXml, but returns a *StrictDecodeError listing
+// the items that v has no place for. v is still decoded in that case.
+func FromXmlStrict(b []byte, v interface{}) error {
+	r, err := parseResponse(b)
+	if err != nil {
+		return err
+	}
+	return r.decodeStrict(v)
 }
 
+// ToXml encodes v as an OPS envelope. Structs and maps become a dt_assoc,
+// slices a dt_array and scalars a dt_scalar. Struct fields are named by
+// their `opensrs` tags.
 func ToXml(v interface{}) (b []byte, err error) {
-	jsonString, _ := json.Marshal(v)
-	var m interface{}
-	json.Unmarshal(jsonString, &m)
-
 	q := OPSEnvelope{Header: Header{Version: "0.9"}, Body: Body{}}
-	dtass, ok := internalEncode(reflect.ValueOf(m)).(DtAssoc)
-	if ok {
-		q.Body.DataBlock.DtAssoc = &dtass
-	} else {
-		return nil, errors.New("Encoding failed")
+	enc, err := encodeValue(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	switch enc := enc.(type) {
+	case DtAssoc:
+		q.Body.DataBlock.DtAssoc = &enc
+	case DtArray:
+		q.Body.DataBlock.DtArray = &enc
+	case string:
+		q.Body.DataBlock.DtScalar = &DtScalar{Value: enc}
+	default:
+		return nil, fmt.Errorf("opensrs: cannot encode %T as a data block", v)
 	}
 	//return xml.MarshalIndent(q, "", " ")
 	return xml.Marshal(q)
@@ -0,0 +1,190 @@
+package opensrs
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func envelope(dataBlock string) []byte {
+	return []byte(`<?xml version='1.0' encoding="UTF-8" standalone="no" ?>
+<!DOCTYPE OPS_envelope SYSTEM "ops.dtd">
+<OPS_envelope>
+    <header>
+        <version>0.9</version>
+        <msg_id>2.21765911726198</msg_id>
+        <msg_type>standard</msg_type>
+    </header>
+    <body>
+        <data_block>` + dataBlock + `</data_block>
+    </body>
+</OPS_envelope>`)
+}
+
+func TestFromXmlNestedScalars(t *testing.T) {
+	b := envelope(`
+            <dt_assoc>
+                <item key="action"><dt_scalar>REPLY</dt_scalar></item>
+                <item key="is_success"><dt_scalar>1</dt_scalar></item>
+                <item key="response_code">200</item>
+                <item key="response_text"><dt_scalarref>Command successful</dt_scalarref></item>
+                <item key="attributes">
+                    <dt_assoc>
+                        <item key="status"><dt_scalar>available</dt_scalar></item>
+                    </dt_assoc>
+                </item>
+            </dt_assoc>`)
+
+	var resp LookupResponse
+	if err := FromXml(b, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := LookupResponse{
+		BaseResponse: BaseResponse{
+			Action:       "REPLY",
+			IsSuccess:    true,
+			ResponseCode: "200",
+			ResponseText: "Command successful",
+		},
+		Attributes: LookupResponseAttributes{Status: "available"},
+	}
+	resp.Raw = nil
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("got\n%+v\nwant\n%+v", resp, want)
+	}
+
+	var m Map
+	if err := FromXml(b, &m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m["action"] != "REPLY" || m["attributes"].(Map)["status"] != "available" {
+		t.Errorf("unexpected map %+v", m)
+	}
+}
+
+func TestFromXmlTopLevelArray(t *testing.T) {
+	b := envelope(`
+            <dt_array>
+                <item key="0"><dt_assoc><item key="domain">a.com</item></dt_assoc></item>
+                <item key="1"><dt_assoc><item key="domain">b.com</item></dt_assoc></item>
+            </dt_array>`)
+
+	var items []NameSuggestItem
+	if err := FromXml(b, &items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].Domain != "a.com" || items[1].Domain != "b.com" {
+		t.Errorf("unexpected items %+v", items)
+	}
+
+	var generic interface{}
+	if err := FromXml(b, &generic); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []interface{}{Map{"domain": "a.com"}, Map{"domain": "b.com"}}
+	if !reflect.DeepEqual(generic, want) {
+		t.Errorf("got %#v, want %#v", generic, want)
+	}
+
+	s := NewArrayStream(strings.NewReader(string(b)), "")
+	var first NameSuggestItem
+	if err := s.Next(&first); err != nil || first.Domain != "a.com" {
+		t.Errorf("stream over top-level array, got %+v, %v", first, err)
+	}
+}
+
+func TestFromXmlTopLevelScalar(t *testing.T) {
+	var s string
+	if err := FromXml(envelope(`<dt_scalar>hello</dt_scalar>`), &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s != "hello" {
+		t.Errorf("want hello, got %q", s)
+	}
+
+	if err := FromXml(envelope(`<dt_scalarref>world</dt_scalarref>`), &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s != "world" {
+		t.Errorf("want world, got %q", s)
+	}
+}
+
+func TestFromXmlObject(t *testing.T) {
+	b := envelope(`
+            <dt_assoc>
+                <item key="contact">
+                    <dt_object class="Contact">
+                        <item key="name">Jane</item>
+                        <item key="email">jane@example.com</item>
+                    </dt_object>
+                </item>
+                <item key="wrapped">
+                    <dt_object class="Contact">
+                        <dt_assoc><item key="name">John</item></dt_assoc>
+                    </dt_object>
+                </item>
+            </dt_assoc>`)
+
+	var v struct {
+		Contact struct {
+			Name  string `opensrs:"name"`
+			Email string `opensrs:"email"`
+		} `opensrs:"contact"`
+		Wrapped Map `opensrs:"wrapped"`
+	}
+	if err := FromXml(b, &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Contact.Name != "Jane" || v.Contact.Email != "jane@example.com" {
+		t.Errorf("unexpected contact %+v", v.Contact)
+	}
+	if v.Wrapped["name"] != "John" {
+		t.Errorf("unexpected wrapped object %+v", v.Wrapped)
+	}
+}
+
+func TestToXmlNonAssocDataBlock(t *testing.T) {
+	b, err := ToXml([]string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `<OPS_envelope><header><version>0.9</version></header><body><data_block><dt_array><item key="0">a</item><item key="1">b</item></dt_array></data_block></body></OPS_envelope>`
+	if string(b) != want {
+		t.Errorf("got\n%s\nwant\n%s", b, want)
+	}
+
+	var out []string
+	if err := FromXml(b, &out); err != nil || !reflect.DeepEqual(out, []string{"a", "b"}) {
+		t.Errorf("round trip got %v, %v", out, err)
+	}
+
+	b, err = ToXml("scalar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(b), "<data_block><dt_scalar>scalar</dt_scalar></data_block>") {
+		t.Errorf("unexpected scalar envelope %s", b)
+	}
+}
+
+func TestArrayStreamSkipsUnrelatedArrays(t *testing.T) {
+	b := envelope(`
+            <dt_assoc>
+                <item key="other"><dt_array><item key="0">x</item></dt_array></item>
+                <item key="attributes"><dt_assoc>
+                    <item key="list"><dt_array><item key="0"><dt_scalar>y</dt_scalar></item></dt_array></item>
+                </dt_assoc></item>
+            </dt_assoc>`)
+
+	s := NewArrayStream(strings.NewReader(string(b)), "attributes/list")
+	var got string
+	if err := s.Next(&got); err != nil || got != "y" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if err := s.Next(&got); err != io.EOF {
+		t.Errorf("want io.EOF, got %v", err)
+	}
+}